@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"facebook-pages-api-go/pkg/facebook"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+)
+
+func main() {
+	accessToken := os.Getenv("PAGE_ACCESS_TOKEN")
+	if accessToken == "" {
+		log.Fatal("❌ PAGE_ACCESS_TOKEN environment variable is required")
+	}
+
+	rulesFile := os.Getenv("ALERT_RULES_FILE")
+	if rulesFile == "" {
+		rulesFile = "alert-rules.json"
+	}
+	apiVersion := os.Getenv("API_VERSION")
+	if apiVersion == "" {
+		apiVersion = facebook.DefaultAPIVersion
+	}
+	rules, err := facebook.LoadAlertRules(rulesFile, apiVersion)
+	if err != nil {
+		log.Fatalf("❌ Loading alert rules: %v", err)
+	}
+
+	var notifiers []facebook.AlertNotifier
+	for _, webhookURL := range splitList(os.Getenv("ALERT_WEBHOOK_URLS")) {
+		notifiers = append(notifiers, facebook.NewWebhookNotifier(webhookURL, os.Getenv("ALERT_WEBHOOK_SECRET")))
+	}
+	if addr := os.Getenv("ALERT_SMTP_ADDR"); addr != "" {
+		to := splitList(os.Getenv("ALERT_EMAIL_TO"))
+		if len(to) == 0 || os.Getenv("ALERT_EMAIL_FROM") == "" {
+			log.Fatal("❌ ALERT_EMAIL_FROM and ALERT_EMAIL_TO are required with ALERT_SMTP_ADDR")
+		}
+		notifiers = append(notifiers, &facebook.SMTPNotifier{
+			Addr:     addr,
+			Username: os.Getenv("ALERT_SMTP_USERNAME"),
+			Password: os.Getenv("ALERT_SMTP_PASSWORD"),
+			From:     os.Getenv("ALERT_EMAIL_FROM"),
+			To:       to,
+		})
+	}
+
+	client := facebook.NewClient(accessToken)
+	client.SetAPIVersion(apiVersion)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	engine := facebook.NewAlertEngine(client, facebook.AlertConfig{
+		Rules:     rules,
+		Notifiers: notifiers,
+		Interval:  parseDuration("ALERT_INTERVAL"),
+		StatePath: os.Getenv("ALERT_STATE_FILE"),
+	})
+
+	// "once" evaluates a single time and exits, e.g. from cron with
+	// ALERT_STATE_FILE set so cooldowns carry over between runs. Comment
+	// volume rules need two evaluations, so they only fire from Run.
+	if len(os.Args) > 1 && os.Args[1] == "once" {
+		if os.Getenv("ALERT_STATE_FILE") == "" {
+			log.Println("⚠️  ALERT_STATE_FILE is not set, repeated runs alert again on the same values")
+		}
+		alerts, err := engine.Evaluate(ctx)
+		for _, alert := range alerts {
+			fmt.Printf("🚨 %s\n", alert.Summary())
+		}
+		if err != nil {
+			log.Fatalf("❌ Evaluation failed: %v", err)
+		}
+		return
+	}
+
+	if len(notifiers) == 0 {
+		fmt.Println("⚠️  No ALERT_WEBHOOK_URLS or ALERT_SMTP_ADDR configured, alerts are only logged")
+	}
+	fmt.Printf("🚀 Evaluating %d alert rules from %s\n", len(rules), rulesFile)
+
+	if err := engine.Run(ctx); err != nil && err != context.Canceled {
+		log.Fatal(err)
+	}
+}
+
+// parseDuration reads an optional duration environment variable
+func parseDuration(name string) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("❌ Invalid %s: %v", name, err)
+	}
+	return d
+}
+
+// splitList splits a comma-separated environment value
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"facebook-pages-api-go/pkg/facebook"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+)
+
+func main() {
+	accessToken := os.Getenv("PAGE_ACCESS_TOKEN")
+	if accessToken == "" {
+		log.Fatal("❌ PAGE_ACCESS_TOKEN environment variable is required")
+	}
+
+	pageID := os.Getenv("PAGE_ID")
+	if len(os.Args) > 1 {
+		pageID = os.Args[1]
+	}
+	if pageID == "" {
+		log.Fatal("❌ Usage: backup <page-id> (or set PAGE_ID)")
+	}
+
+	dir := os.Getenv("BACKUP_DIR")
+	if dir == "" {
+		dir = "backup-" + pageID
+	}
+
+	client := facebook.NewClient(accessToken)
+	if apiVersion := os.Getenv("API_VERSION"); apiVersion != "" {
+		client.SetAPIVersion(apiVersion)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	fmt.Printf("📦 Backing up photos of page %s to %s\n", pageID, dir)
+
+	result, err := client.BackupPhotos(ctx, pageID, facebook.BackupOptions{Dir: dir})
+	if result != nil {
+		fmt.Printf("📸 %d albums: %d downloaded, %d already present, %d failed\n",
+			result.Albums, result.Downloaded, result.Skipped, result.Failed)
+		for _, failure := range result.Errors {
+			fmt.Printf("  ⚠️  %s\n", failure)
+		}
+	}
+	if err != nil {
+		log.Fatalf("❌ Backup incomplete (rerun to resume): %v", err)
+	}
+
+	fmt.Printf("✅ Manifest written to %s/%s\n", dir, facebook.BackupManifestName)
+}
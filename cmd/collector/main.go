@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"facebook-pages-api-go/pkg/facebook"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func main() {
+	accessToken := os.Getenv("PAGE_ACCESS_TOKEN")
+	if accessToken == "" {
+		log.Fatal("❌ PAGE_ACCESS_TOKEN environment variable is required")
+	}
+
+	// Comma-separated list of pages to collect, falls back to PAGE_ID
+	pageIDs := splitList(os.Getenv("COLLECTOR_PAGE_IDS"))
+	if len(pageIDs) == 0 && os.Getenv("PAGE_ID") != "" {
+		pageIDs = []string{os.Getenv("PAGE_ID")}
+	}
+	if len(pageIDs) == 0 {
+		log.Fatal("❌ COLLECTOR_PAGE_IDS or PAGE_ID environment variable is required")
+	}
+
+	storeDir := os.Getenv("INSIGHTS_STORE_DIR")
+	if storeDir == "" {
+		storeDir = "insights-store"
+	}
+
+	store, err := facebook.OpenInsightStore(storeDir)
+	if err != nil {
+		log.Fatalf("❌ Opening insights store: %v", err)
+	}
+	if err := store.Compact(); err != nil {
+		log.Fatalf("❌ Compacting insights store: %v", err)
+	}
+
+	client := facebook.NewClient(accessToken)
+	if apiVersion := os.Getenv("API_VERSION"); apiVersion != "" {
+		client.SetAPIVersion(apiVersion)
+	}
+
+	config := facebook.CollectorConfig{
+		PageIDs:     pageIDs,
+		PageMetrics: splitList(os.Getenv("COLLECTOR_PAGE_METRICS")),
+		PostMetrics: splitList(os.Getenv("COLLECTOR_POST_METRICS")),
+		Period:      os.Getenv("COLLECTOR_PERIOD"),
+		Interval:    parseDuration("COLLECTOR_INTERVAL"),
+		Lookback:    parseDuration("COLLECTOR_LOOKBACK"),
+	}
+	if recent := os.Getenv("COLLECTOR_RECENT_POSTS"); recent != "" {
+		n, err := strconv.Atoi(recent)
+		if err != nil {
+			log.Fatalf("❌ Invalid COLLECTOR_RECENT_POSTS: %v", err)
+		}
+		config.RecentPosts = n
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	collector := facebook.NewCollector(client, store, config)
+
+	// "once" collects a single time and exits, e.g. from cron
+	if len(os.Args) > 1 && os.Args[1] == "once" {
+		result, err := collector.CollectOnce(ctx)
+		if err != nil {
+			log.Fatalf("❌ Collection failed: %v", err)
+		}
+		fmt.Printf("📈 %d points fetched, %d new or changed\n", result.Points, result.Stored)
+		for _, failure := range result.Errors {
+			fmt.Printf("  ⚠️  %s\n", failure)
+		}
+		return
+	}
+
+	fmt.Printf("🚀 Collecting insights for pages %s into %s\n", strings.Join(pageIDs, ", "), storeDir)
+
+	if err := collector.Run(ctx); err != nil && err != context.Canceled {
+		log.Fatal(err)
+	}
+}
+
+// parseDuration reads an optional duration environment variable
+func parseDuration(name string) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("❌ Invalid %s: %v", name, err)
+	}
+	return d
+}
+
+// splitList splits a comma-separated environment value
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"facebook-pages-api-go/pkg/facebook"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+)
+
+func main() {
+	accessToken := os.Getenv("PAGE_ACCESS_TOKEN")
+	if accessToken == "" {
+		log.Fatal("❌ PAGE_ACCESS_TOKEN environment variable is required")
+	}
+
+	// Comma-separated list of pages to watch, falls back to PAGE_ID
+	pageIDs := splitList(os.Getenv("FANOUT_PAGE_IDS"))
+	if len(pageIDs) == 0 && os.Getenv("PAGE_ID") != "" {
+		pageIDs = []string{os.Getenv("PAGE_ID")}
+	}
+
+	deadLetterPath := os.Getenv("FANOUT_DEADLETTER_PATH")
+	if deadLetterPath == "" {
+		deadLetterPath = "fanout-deadletter.ndjson"
+	}
+
+	dispatcher := facebook.NewDispatcher(facebook.NewDeadLetterStore(deadLetterPath))
+
+	// Outbound HTTP callbacks signed with FANOUT_SECRET
+	secret := os.Getenv("FANOUT_SECRET")
+	for _, callbackURL := range splitList(os.Getenv("FANOUT_CALLBACK_URLS")) {
+		dispatcher.AddSink(facebook.NewHTTPSink(callbackURL, secret))
+		fmt.Printf("🔗 Callback sink: %s\n", callbackURL)
+	}
+
+	// NDJSON file sink
+	if path := os.Getenv("FANOUT_NDJSON_PATH"); path != "" {
+		sink, err := facebook.NewNDJSONSink(path)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		defer sink.Close()
+		dispatcher.AddSink(sink)
+		fmt.Printf("📝 NDJSON sink: %s\n", path)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// "replay" redelivers the dead-letter file and exits
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		result, err := dispatcher.Replay(ctx)
+		if err != nil {
+			log.Fatalf("❌ Replay failed: %v", err)
+		}
+		fmt.Printf("♻️  Replayed %s: %d delivered, %d failed, %d skipped\n",
+			deadLetterPath, result.Delivered, result.Failed, result.Skipped)
+		return
+	}
+
+	if len(pageIDs) == 0 {
+		log.Fatal("❌ FANOUT_PAGE_IDS or PAGE_ID environment variable is required")
+	}
+
+	client := facebook.NewClient(accessToken)
+	if apiVersion := os.Getenv("API_VERSION"); apiVersion != "" {
+		client.SetAPIVersion(apiVersion)
+	}
+
+	poller := facebook.NewPoller(client, dispatcher, pageIDs...)
+	if interval := os.Getenv("FANOUT_POLL_INTERVAL"); interval != "" {
+		d, err := time.ParseDuration(interval)
+		if err != nil {
+			log.Fatalf("❌ Invalid FANOUT_POLL_INTERVAL: %v", err)
+		}
+		poller.Interval = d
+	}
+
+	fmt.Printf("🚀 Forwarding activity for pages %s every %s\n", strings.Join(pageIDs, ", "), poller.Interval)
+	fmt.Printf("💀 Dead-letter file: %s (run with \"replay\" to redeliver)\n", deadLetterPath)
+
+	if err := poller.Run(ctx); err != nil && err != context.Canceled {
+		log.Fatal(err)
+	}
+}
+
+// splitList splits a comma-separated environment value
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
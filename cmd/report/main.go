@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"facebook-pages-api-go/pkg/facebook"
+	"log"
+	"net/url"
+	"os"
+	"os/signal"
+)
+
+func main() {
+	accessToken := os.Getenv("PAGE_ACCESS_TOKEN")
+	if accessToken == "" {
+		log.Fatal("❌ PAGE_ACCESS_TOKEN environment variable is required")
+	}
+
+	// Page from the first argument, falls back to PAGE_ID
+	pageID := os.Getenv("PAGE_ID")
+	if len(os.Args) > 1 {
+		pageID = os.Args[1]
+	}
+	if pageID == "" {
+		log.Fatal("❌ Usage: report <page-id> (or set PAGE_ID)")
+	}
+
+	// Options use the same names and formats as the /reports/compare endpoint
+	query := url.Values{}
+	for param, name := range map[string]string{
+		"period":    "REPORT_PERIOD",
+		"metric":    "REPORT_METRICS",
+		"since":     "REPORT_SINCE",
+		"until":     "REPORT_UNTIL",
+		"threshold": "REPORT_THRESHOLD",
+		"format":    "REPORT_FORMAT",
+	} {
+		if value := os.Getenv(name); value != "" {
+			query.Set(param, value)
+		}
+	}
+	if query.Get("format") == "" {
+		query.Set("format", facebook.ReportMarkdown)
+	}
+
+	opts, format, err := facebook.ParseComparisonOptions(query)
+	if err != nil {
+		log.Fatalf("❌ Invalid report options: %v", err)
+	}
+
+	client := facebook.NewClient(accessToken)
+	if apiVersion := os.Getenv("API_VERSION"); apiVersion != "" {
+		client.SetAPIVersion(apiVersion)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	report, err := client.ComparePeriods(ctx, pageID, opts)
+	if err != nil {
+		log.Fatalf("❌ Building report: %v", err)
+	}
+
+	if err := facebook.WriteComparisonReport(os.Stdout, format, report); err != nil {
+		log.Fatalf("❌ Writing report: %v", err)
+	}
+}
@@ -1,11 +1,14 @@
 package main
 
 import (
+	"context"
 	"facebook-pages-api-go/pkg/facebook"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strings"
+	"time"
 )
 
 func main() {
@@ -15,6 +18,76 @@ func main() {
 	// Create simple router (supports request-level authentication)
 	router := facebook.NewSimpleRouter(accessToken)
 	
+	// Enable the webhook receiver when app credentials are configured
+	appSecret := os.Getenv("FB_APP_SECRET")
+	verifyToken := os.Getenv("FB_VERIFY_TOKEN")
+	if appSecret != "" && verifyToken != "" {
+		router.SetWebhookHandler(newWebhookHandler(verifyToken, appSecret, router.MessagingWindow()))
+	}
+	
+	// Reconcile webhook subscriptions across managed pages when an app ID and
+	// an admin token guarding the endpoints are configured
+	appID := os.Getenv("FB_APP_ID")
+	adminToken := os.Getenv("ADMIN_TOKEN")
+	if appID != "" && adminToken == "" {
+		log.Println("⚠️  FB_APP_ID is set but ADMIN_TOKEN is not, subscription endpoints are disabled")
+	}
+	if appID != "" && adminToken != "" {
+		fields := []string{"feed", "mention", "ratings", "messages"}
+		if configured := os.Getenv("FB_SUBSCRIBED_FIELDS"); configured != "" {
+			fields = strings.Split(configured, ",")
+		}
+		router.SetDesiredSubscriptions(appID, fields, adminToken)
+	}
+	
+	// Serve collected insights history when a store directory is configured
+	storeDir := os.Getenv("INSIGHTS_STORE_DIR")
+	if storeDir != "" {
+		store, err := facebook.OpenInsightStore(storeDir)
+		if err != nil {
+			log.Fatalf("❌ Opening insights store: %v", err)
+		}
+		router.SetInsightStore(store)
+	}
+	
+	// Serve operational metrics on /metrics
+	registry := facebook.NewMetricsRegistry()
+	router.SetMetricsRegistry(registry)
+	
+	// Trace requests and Graph calls as JSON lines when TRACE_FILE is set ("-" for stdout)
+	if traceFile := os.Getenv("TRACE_FILE"); traceFile != "" {
+		traceOutput := os.Stdout
+		if traceFile != "-" {
+			f, err := os.OpenFile(traceFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+			if err != nil {
+				log.Fatalf("❌ Opening trace file: %v", err)
+			}
+			traceOutput = f
+		}
+		router.SetTracer(facebook.NewTracer("facebook-pages-api-simple", facebook.NewJSONExporter(traceOutput)))
+	}
+	
+	// Export page KPIs alongside them, refreshed in the background
+	metricsPages := splitList(os.Getenv("METRICS_PAGE_IDS"))
+	if len(metricsPages) > 0 {
+		if accessToken == "" {
+			log.Fatal("❌ METRICS_PAGE_IDS requires PAGE_ACCESS_TOKEN")
+		}
+		
+		config := facebook.PageMetricsConfig{PageIDs: metricsPages}
+		if interval := os.Getenv("METRICS_REFRESH_INTERVAL"); interval != "" {
+			d, err := time.ParseDuration(interval)
+			if err != nil {
+				log.Fatalf("❌ Invalid METRICS_REFRESH_INTERVAL: %v", err)
+			}
+			config.Interval = d
+		}
+		
+		client := facebook.NewClient(accessToken)
+		client.Metrics = registry
+		go facebook.NewPageMetricsPoller(client, registry, config).Run(context.Background())
+	}
+	
 	// Set port
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -35,6 +108,36 @@ func main() {
 	fmt.Println("  GET /api/posts/{postId}/comments      - Get post comments")
 	fmt.Println("  GET /api/comments/{commentId}         - Get specific comment")
 	fmt.Println("  GET /api/comments/{commentId}/replies - Get comment replies")
+	fmt.Println("  GET /api/pages/{pageId}/conversations - Get page inbox conversations")
+	fmt.Println("  GET /api/conversations/{id}/messages  - Get conversation messages")
+	fmt.Println("  POST /api/pages/{pageId}/messages     - Send a Messenger message")
+	fmt.Println("  GET /api/pages/{pageId}/insights      - Get page insights (metric, period, since, until)")
+	fmt.Println("  GET /api/pages/{pageId}/insights/metrics - List available page metrics")
+	fmt.Println("  GET /api/posts/{postId}/insights      - Get post insights")
+	fmt.Println("  GET /api/pages/{pageId}/analytics     - Post performance analytics (limit, tz)")
+	fmt.Println("  GET /api/pages/{pageId}/reports/compare - Period-over-period report (period, metric, format)")
+	fmt.Println("  GET /api/pages/{pageId}/photos        - Get page photos")
+	fmt.Println("  POST /api/pages/{pageId}/photos       - Upload a photo (multipart file or JSON url)")
+	fmt.Println("  DELETE /api/photos/{photoId}          - Delete a photo")
+	fmt.Println("  GET /api/pages/{pageId}/albums        - Get page photo albums")
+	fmt.Println("  POST /api/pages/{pageId}/albums       - Create a photo album")
+	fmt.Println("  GET /api/albums/{albumId}/photos      - Get album photos")
+	fmt.Println("  POST /api/albums/{albumId}/photos     - Upload a photo to an album")
+	fmt.Println("  POST /api/pages/{pageId}/reels        - Publish a reel (multipart video)")
+	fmt.Println("  POST /api/pages/{pageId}/stories      - Publish a photo or video story")
+	fmt.Println("  GET /api/videos/{videoId}/status      - Get video processing status")
+	if appSecret != "" && verifyToken != "" {
+		fmt.Println("  GET|POST /webhooks/facebook           - Facebook webhook receiver")
+	}
+	fmt.Println("  GET /metrics                          - Prometheus service metrics and page KPIs")
+	if storeDir != "" {
+		fmt.Println("  GET /api/pages/{pageId}/insights/history - Collected page insights history")
+		fmt.Println("  GET /api/posts/{postId}/insights/history - Collected post insights history")
+	}
+	if appID != "" && adminToken != "" {
+		fmt.Println("  GET  /admin/subscriptions             - Preview webhook subscription changes")
+		fmt.Println("  POST /admin/subscriptions/reconcile   - Apply webhook subscriptions to all pages")
+	}
 	fmt.Println()
 	fmt.Println("📖 Query parameters:")
 	fmt.Println("  ?fields=field1,field2  - Select specific fields")
@@ -47,3 +150,42 @@ func main() {
 	// Start server
 	log.Fatal(http.ListenAndServe(":"+port, router))
 }
+
+// newWebhookHandler creates a webhook receiver that logs incoming page events
+// and keeps the messaging window tracker up to date
+func newWebhookHandler(verifyToken, appSecret string, messagingWindow *facebook.MessagingWindowTracker) *facebook.WebhookHandler {
+	webhooks := facebook.NewWebhookHandler(verifyToken, appSecret)
+	
+	webhooks.OnFeed(func(pageID string, change facebook.FeedChange) error {
+		log.Printf("📨 Feed event on page %s: %s %s (post %s, comment %s)", pageID, change.Item, change.Verb, change.PostID, change.CommentID)
+		return nil
+	})
+	webhooks.OnMention(func(pageID string, change facebook.MentionChange) error {
+		log.Printf("📨 Mention of page %s: %s %s (post %s)", pageID, change.Item, change.Verb, change.PostID)
+		return nil
+	})
+	webhooks.OnRating(func(pageID string, change facebook.RatingChange) error {
+		log.Printf("📨 Rating on page %s: %s %s by %s", pageID, change.Item, change.Verb, change.ReviewerName)
+		return nil
+	})
+	webhooks.OnMessage(func(pageID string, event facebook.MessagingEvent) error {
+		log.Printf("📨 Message event on page %s from %s", pageID, event.Sender.ID)
+		if event.Message != nil && !event.Message.IsEcho {
+			messagingWindow.RecordUserMessage(pageID, event.Sender.ID, event.Time())
+		}
+		return nil
+	})
+	
+	return webhooks
+}
+
+// splitList splits a comma-separated environment value
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
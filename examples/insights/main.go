@@ -24,12 +24,12 @@ func main() {
 
 	// Common page metrics
 	metrics := []string{
-		"page_fans",
-		"page_fan_adds",
-		"page_fan_removes",
+		"page_follows",
+		"page_daily_follows_unique",
+		"page_daily_unfollows_unique",
 		"page_views_total",
 		"page_impressions",
-		"page_engaged_users",
+		"page_post_engagements",
 	}
 
 	insights, err := client.GetPageInsights(pageID, metrics, "day", &since, &until)
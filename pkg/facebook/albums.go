@@ -0,0 +1,162 @@
+package facebook
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// AlbumsResponse represents a paginated list of albums
+type AlbumsResponse struct {
+	Data   []Album    `json:"data"`
+	Paging PagingData `json:"paging,omitempty"`
+}
+
+// PhotosResponse represents a paginated list of photos
+type PhotosResponse struct {
+	Data   []Photo    `json:"data"`
+	Paging PagingData `json:"paging,omitempty"`
+}
+
+// defaultAlbumFields are requested when no album fields are given
+var defaultAlbumFields = []string{
+	"id", "name", "description", "count", "link", "type",
+	"can_upload", "cover_photo", "created_time", "updated_time",
+}
+
+// GetAlbums retrieves the photo albums of a Facebook page.
+// Pass the after cursor of a previous response to read the next page.
+func (c *Client) GetAlbums(pageID string, limit int, after string, fields ...string) (*AlbumsResponse, error) {
+	params := url.Values{}
+
+	if limit > 0 {
+		params.Set("limit", fmt.Sprintf("%d", limit))
+	}
+
+	if after != "" {
+		params.Set("after", after)
+	}
+
+	if len(fields) == 0 {
+		fields = defaultAlbumFields
+	}
+	params.Set("fields", strings.Join(fields, ","))
+
+	endpoint := fmt.Sprintf("%s/albums", pageID)
+	resp, err := c.makeRequest("GET", endpoint, params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting albums: %w", err)
+	}
+
+	var albumsResp AlbumsResponse
+	if err := c.handleResponse(resp, &albumsResp); err != nil {
+		return nil, err
+	}
+
+	return &albumsResp, nil
+}
+
+// GetAlbum retrieves a single album
+func (c *Client) GetAlbum(albumID string, fields ...string) (*Album, error) {
+	params := url.Values{}
+
+	if len(fields) == 0 {
+		fields = defaultAlbumFields
+	}
+	params.Set("fields", strings.Join(fields, ","))
+
+	resp, err := c.makeRequest("GET", albumID, params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting album: %w", err)
+	}
+
+	var album Album
+	if err := c.handleResponse(resp, &album); err != nil {
+		return nil, err
+	}
+
+	return &album, nil
+}
+
+// CreateAlbum creates a photo album on a Facebook page
+func (c *Client) CreateAlbum(pageID string, name string, description string) (*Album, error) {
+	if name == "" {
+		return nil, fmt.Errorf("album name is required")
+	}
+
+	params := url.Values{}
+	params.Set("name", name)
+
+	if description != "" {
+		params.Set("message", description)
+	}
+
+	endpoint := fmt.Sprintf("%s/albums", pageID)
+	resp, err := c.makeRequest("POST", endpoint, params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating album: %w", err)
+	}
+
+	var createResp struct {
+		ID string `json:"id"`
+	}
+
+	if err := c.handleResponse(resp, &createResp); err != nil {
+		return nil, err
+	}
+
+	return &Album{
+		ID:          createResp.ID,
+		Name:        name,
+		Description: description,
+	}, nil
+}
+
+// UploadPhotoToAlbum uploads a photo file into an album
+func (c *Client) UploadPhotoToAlbum(albumID string, imagePath string, message string) (*PhotoResponse, error) {
+	// Albums expose the same photos edge as pages
+	return c.UploadPhotoContext(c.requestContext(), albumID, imagePath, message, true, nil)
+}
+
+// UploadPhotoToAlbumFromReader streams a photo into an album. The photo is
+// validated like UploadPhotoFromReaderContext.
+func (c *Client) UploadPhotoToAlbumFromReader(ctx context.Context, albumID string, reader io.Reader, message string, opts *UploadOptions) (*PhotoResponse, error) {
+	return c.UploadPhotoFromReaderContext(ctx, albumID, reader, message, true, opts)
+}
+
+// GetAlbumPhotos retrieves the photos of an album.
+// Pass the after cursor of a previous response to read the next page.
+func (c *Client) GetAlbumPhotos(albumID string, limit int, after string, fields ...string) (*PhotosResponse, error) {
+	params := url.Values{}
+
+	if limit > 0 {
+		params.Set("limit", fmt.Sprintf("%d", limit))
+	}
+
+	if after != "" {
+		params.Set("after", after)
+	}
+
+	if len(fields) == 0 {
+		fields = []string{
+			"id", "name", "picture", "source", "created_time", "updated_time",
+			"link", "width", "height",
+		}
+	}
+	params.Set("fields", strings.Join(fields, ","))
+
+	endpoint := fmt.Sprintf("%s/photos", albumID)
+	resp, err := c.makeRequest("GET", endpoint, params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting album photos: %w", err)
+	}
+
+	var photosResp PhotosResponse
+	if err := c.handleResponse(resp, &photosResp); err != nil {
+		return nil, err
+	}
+
+	return &photosResp, nil
+}
@@ -0,0 +1,164 @@
+package facebook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeAlbums serves the albums of page_1 and records album and photo writes
+type fakeAlbums struct {
+	mu       sync.Mutex
+	requests []string
+}
+
+func (f *fakeAlbums) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(req.URL.Path, "/"+DefaultAPIVersion+"/")
+	switch {
+	case req.Method == "GET" && path == "page_1/albums":
+		query := req.URL.Query()
+		f.requests = append(f.requests, fmt.Sprintf("GET albums limit=%s after=%s fields=%s", query.Get("limit"), query.Get("after"), query.Get("fields")))
+		fmt.Fprint(w, `{"data":[{"id":"album_1","name":"Launch","count":3}],"paging":{"cursors":{"after":"next"}}}`)
+	case req.Method == "POST" && path == "page_1/albums":
+		query := req.URL.Query()
+		f.requests = append(f.requests, fmt.Sprintf("POST albums name=%s message=%s", query.Get("name"), query.Get("message")))
+		fmt.Fprint(w, `{"id":"album_2"}`)
+	case req.Method == "POST" && path == "album_1/photos":
+		source := req.FormValue("url")
+		if _, _, err := req.FormFile("source"); err == nil {
+			source = "file"
+		}
+		f.requests = append(f.requests, fmt.Sprintf("POST album photo %s message=%s", source, req.FormValue("message")))
+		fmt.Fprint(w, `{"id":"photo_1"}`)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":{"message":"Unknown path","type":"GraphMethodException","code":100}}`)
+	}
+}
+
+func (f *fakeAlbums) recorded() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	requests := f.requests
+	f.requests = nil
+	return requests
+}
+
+func TestGetAlbumsAndCreateAlbum(t *testing.T) {
+	fake := &fakeAlbums{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client := NewClient("test_token")
+	client.BaseURL = server.URL
+
+	albums, err := client.GetAlbums("page_1", 10, "cursor", "id", "name")
+	if err != nil {
+		t.Fatalf("Expected GetAlbums to succeed, got %v", err)
+	}
+	if len(albums.Data) != 1 || albums.Data[0].ID != "album_1" {
+		t.Errorf("Unexpected albums %+v", albums.Data)
+	}
+
+	if _, err := client.GetAlbums("page_1", 0, ""); err != nil {
+		t.Fatalf("Expected GetAlbums with defaults to succeed, got %v", err)
+	}
+
+	album, err := client.CreateAlbum("page_1", "Launch", "Launch day photos")
+	if err != nil {
+		t.Fatalf("Expected CreateAlbum to succeed, got %v", err)
+	}
+	if album.ID != "album_2" || album.Name != "Launch" || album.Description != "Launch day photos" {
+		t.Errorf("Unexpected album %+v", album)
+	}
+
+	if _, err := client.CreateAlbum("page_1", "", ""); err == nil {
+		t.Error("Expected CreateAlbum to require a name")
+	}
+
+	want := []string{
+		"GET albums limit=10 after=cursor fields=id,name",
+		"GET albums limit= after= fields=" + strings.Join(defaultAlbumFields, ","),
+		"POST albums name=Launch message=Launch day photos",
+	}
+	if got := fake.recorded(); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected requests %v, got %v", want, got)
+	}
+}
+
+func TestAlbumRoutes(t *testing.T) {
+	fake := &fakeAlbums{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	router := NewRouter("test_token")
+	router.defaultClient.BaseURL = server.URL
+
+	simple := NewSimpleRouter("test_token")
+	simple.defaultClient.BaseURL = server.URL
+
+	handlers := map[string]http.Handler{"Router": router.SetupRoutes(), "SimpleRouter": simple}
+	for name, handler := range handlers {
+		send := func(req *http.Request) *httptest.ResponseRecorder {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			return rec
+		}
+
+		rec := send(httptest.NewRequest("GET", "/api/pages/page_1/albums?limit=5", nil))
+		var albums AlbumsResponse
+		if rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), &albums) != nil || len(albums.Data) != 1 {
+			t.Errorf("%s: unexpected albums response %d: %s", name, rec.Code, rec.Body.String())
+		}
+
+		if rec := send(httptest.NewRequest("POST", "/api/pages/page_1/albums", strings.NewReader(`{"description":"No name"}`))); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400 without an album name, got %d", name, rec.Code)
+		}
+		if rec := send(httptest.NewRequest("POST", "/api/pages/page_1/albums", strings.NewReader(`{"name":"Launch"}`))); rec.Code != http.StatusCreated {
+			t.Errorf("%s: expected 201 for a new album, got %d: %s", name, rec.Code, rec.Body.String())
+		}
+
+		rec = send(httptest.NewRequest("POST", "/api/albums/album_1/photos", strings.NewReader(`{"url":"https://example.com/a.png","message":"By URL"}`)))
+		if rec.Code != http.StatusCreated {
+			t.Errorf("%s: expected 201 for a photo by URL, got %d: %s", name, rec.Code, rec.Body.String())
+		}
+
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		writer.WriteField("message", "By file")
+		part, _ := writer.CreateFormFile("file", "photo.png")
+		part.Write(encodePNG(t, 10, 10))
+		writer.Close()
+
+		req := httptest.NewRequest("POST", "/api/albums/album_1/photos", &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		rec = send(req)
+		var photo PhotoResponse
+		if rec.Code != http.StatusCreated || json.Unmarshal(rec.Body.Bytes(), &photo) != nil || photo.ID != "photo_1" {
+			t.Errorf("%s: expected 201 for a photo file, got %d: %s", name, rec.Code, rec.Body.String())
+		}
+
+		if rec := send(httptest.NewRequest("POST", "/api/albums/album_1/photos", strings.NewReader(`{}`))); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400 without a url or file, got %d", name, rec.Code)
+		}
+
+		want := []string{
+			"GET albums limit=5 after= fields=" + strings.Join(defaultAlbumFields, ","),
+			"POST albums name=Launch message=",
+			"POST album photo https://example.com/a.png message=By URL",
+			"POST album photo file message=By file",
+		}
+		if got := fake.recorded(); strings.Join(got, "\n") != strings.Join(want, "\n") {
+			t.Errorf("%s: expected requests %v, got %v", name, want, got)
+		}
+	}
+}
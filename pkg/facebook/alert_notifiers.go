@@ -0,0 +1,129 @@
+package facebook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// AlertIDHeader carries the ID of a delivered alert
+const AlertIDHeader = "X-Alert-ID"
+
+// WebhookNotifier posts alerts as signed JSON, with the same
+// X-Event-Signature scheme as HTTPSink
+type WebhookNotifier struct {
+	URL        string
+	Secret     string
+	HTTPClient *http.Client
+}
+
+// NewWebhookNotifier creates a notifier posting to webhookURL; an empty secret
+// leaves requests unsigned
+func NewWebhookNotifier(webhookURL, secret string) *WebhookNotifier {
+	return &WebhookNotifier{
+		URL:    webhookURL,
+		Secret: secret,
+		HTTPClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Name implements AlertNotifier
+func (n *WebhookNotifier) Name() string {
+	return "webhook:" + n.URL
+}
+
+// Notify implements AlertNotifier
+func (n *WebhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("marshaling alert: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", n.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(AlertIDHeader, alert.ID)
+	if n.Secret != "" {
+		req.Header.Set(EventSignatureHeader, SignEvent(n.Secret, time.Now(), body))
+	}
+
+	resp, err := n.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("making request: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// SMTPNotifier emails alerts as plain text
+type SMTPNotifier struct {
+	// Addr is the host:port of the SMTP server
+	Addr string
+	// Username and Password enable PLAIN authentication when set
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+// Name implements AlertNotifier
+func (n *SMTPNotifier) Name() string {
+	return "smtp:" + n.Addr
+}
+
+// Notify implements AlertNotifier. net/smtp has no context support, so ctx
+// is only checked before sending.
+func (n *SMTPNotifier) Notify(ctx context.Context, alert Alert) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if n.Username != "" {
+		host, _, _ := strings.Cut(n.Addr, ":")
+		auth = smtp.PlainAuth("", n.Username, n.Password, host)
+	}
+
+	if err := smtp.SendMail(n.Addr, auth, n.From, n.To, alertEmail(n.From, n.To, alert)); err != nil {
+		return fmt.Errorf("sending email: %w", err)
+	}
+	return nil
+}
+
+// alertEmail renders an alert as an RFC 5322 message
+func alertEmail(from string, to []string, alert Alert) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", strings.NewReplacer("\r", " ", "\n", " ").Replace(alert.Summary()))
+	fmt.Fprintf(&b, "Date: %s\r\n", alert.FiredAt.Format(time.RFC1123Z))
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+
+	fmt.Fprintf(&b, "Rule:       %s\r\n", alert.Rule)
+	fmt.Fprintf(&b, "Page:       %s\r\n", alert.PageID)
+	fmt.Fprintf(&b, "Object:     %s\r\n", alert.ObjectID)
+	fmt.Fprintf(&b, "Metric:     %s = %g\r\n", alert.Metric, alert.Value)
+	fmt.Fprintf(&b, "Conditions: %s\r\n", strings.Join(alert.Conditions, ", "))
+	if alert.ZScore != nil {
+		fmt.Fprintf(&b, "Baseline:   mean %.2f, stddev %.2f, z-score %.2f\r\n", alert.Mean, alert.StdDev, *alert.ZScore)
+	}
+	fmt.Fprintf(&b, "Observed:   %s\r\n", alert.ObservedAt.Format(time.RFC3339))
+	return []byte(b.String())
+}
@@ -0,0 +1,607 @@
+package facebook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AlertSource is what an alert rule watches
+type AlertSource string
+
+const (
+	// AlertSourcePageMetric watches the latest daily value of a page insights metric
+	AlertSourcePageMetric AlertSource = "page_metric"
+	// AlertSourceCommentVolume watches new comments per hour on recent posts
+	AlertSourceCommentVolume AlertSource = "comment_volume"
+)
+
+// commentVolumeMetric names the value of comment volume alerts
+const commentVolumeMetric = "comments_per_hour"
+
+// Defaults of AlertRule
+const (
+	defaultAlertBaseline    = 14
+	defaultAlertCooldown    = time.Hour
+	defaultAlertRecentPosts = 10
+	// minBaselineSamples is the history a z-score needs to be meaningful
+	minBaselineSamples = 3
+)
+
+// JSONDuration is a time.Duration that reads and writes JSON as "90s", "1h" etc.
+type JSONDuration time.Duration
+
+// UnmarshalJSON accepts a Go duration string
+func (d *JSONDuration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("duration must be a string such as \"1h\": %w", err)
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = JSONDuration(parsed)
+	return nil
+}
+
+// MarshalJSON writes the duration as a Go duration string
+func (d JSONDuration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// AlertRule describes when to alert on a page metric or comment volume. A
+// rule fires when any of its conditions holds: the value is above Above,
+// below Below, or its z-score against the rolling baseline reaches ZScore
+// (a negative ZScore watches for drops).
+type AlertRule struct {
+	Name     string      `json:"name"`
+	Severity string      `json:"severity,omitempty"`
+	PageID   string      `json:"page_id"`
+	Source   AlertSource `json:"source"`
+	// Metric is the page insights metric of page_metric rules. The negative
+	// feedback metrics (page_negative_feedback and its variants) were removed
+	// in v18.0 without a replacement; page_daily_unfollows_unique is the
+	// closest signal insights still expose.
+	Metric string `json:"metric,omitempty"`
+	// RecentPosts is how many latest posts comment_volume rules watch (default 10)
+	RecentPosts int `json:"recent_posts,omitempty"`
+
+	Above  *float64 `json:"above,omitempty"`
+	Below  *float64 `json:"below,omitempty"`
+	ZScore float64  `json:"zscore,omitempty"`
+	// Baseline is the number of past values the z-score is computed over
+	// (default 14): days for page metrics, evaluations for comment volume
+	Baseline int `json:"baseline,omitempty"`
+	// Cooldown suppresses repeats of the rule for the same page or post (default 1h)
+	Cooldown JSONDuration `json:"cooldown,omitempty"`
+}
+
+// Validate checks that the rule is complete
+func (r *AlertRule) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("alert rule: name is required")
+	}
+	if r.PageID == "" {
+		return fmt.Errorf("alert rule %s: page_id is required", r.Name)
+	}
+	switch r.Source {
+	case AlertSourcePageMetric:
+		if r.Metric == "" {
+			return fmt.Errorf("alert rule %s: metric is required for %s", r.Name, r.Source)
+		}
+	case AlertSourceCommentVolume:
+	default:
+		return fmt.Errorf("alert rule %s: unknown source %q", r.Name, r.Source)
+	}
+	if r.Above == nil && r.Below == nil && r.ZScore == 0 {
+		return fmt.Errorf("alert rule %s: one of above, below or zscore is required", r.Name)
+	}
+	return nil
+}
+
+// LoadAlertRules reads and validates a JSON array of rules. The metrics of
+// page_metric rules are checked against the catalog for apiVersion, so a
+// rule on a deprecated metric fails here rather than on every evaluation.
+func LoadAlertRules(path, apiVersion string) ([]AlertRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading alert rules: %w", err)
+	}
+
+	var rules []AlertRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("parsing alert rules: %w", err)
+	}
+	for i := range rules {
+		if err := rules[i].Validate(); err != nil {
+			return nil, err
+		}
+		if rules[i].Source != AlertSourcePageMetric {
+			continue
+		}
+		if err := ValidateMetrics(MetricObjectPage, []string{rules[i].Metric}, PeriodDay, apiVersion); err != nil {
+			return nil, fmt.Errorf("alert rule %s: %w", rules[i].Name, err)
+		}
+	}
+	return rules, nil
+}
+
+// Alert is a fired rule
+type Alert struct {
+	ID       string `json:"id"`
+	Rule     string `json:"rule"`
+	Severity string `json:"severity,omitempty"`
+	PageID   string `json:"page_id"`
+	// ObjectID is the page or post the value belongs to
+	ObjectID string  `json:"object_id"`
+	Metric   string  `json:"metric"`
+	Value    float64 `json:"value"`
+	// Mean, StdDev and ZScore describe the baseline when one was available
+	Mean   float64  `json:"mean,omitempty"`
+	StdDev float64  `json:"stddev,omitempty"`
+	ZScore *float64 `json:"zscore,omitempty"`
+	// Conditions lists the conditions that held, e.g. "above 100"
+	Conditions []string  `json:"conditions"`
+	ObservedAt time.Time `json:"observed_at"`
+	FiredAt    time.Time `json:"fired_at"`
+}
+
+// Summary describes the alert in one line
+func (a *Alert) Summary() string {
+	return fmt.Sprintf("[%s] %s: %s of %s is %s (%s)",
+		a.Severity, a.Rule, a.Metric, a.ObjectID, strconv.FormatFloat(a.Value, 'f', -1, 64), strings.Join(a.Conditions, ", "))
+}
+
+// AlertNotifier delivers fired alerts
+type AlertNotifier interface {
+	// Name identifies the notifier in logs
+	Name() string
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// AlertConfig configures an AlertEngine
+type AlertConfig struct {
+	Rules     []AlertRule
+	Notifiers []AlertNotifier
+	// Interval between evaluations of Run (default 5 minutes)
+	Interval time.Duration
+	// Logf reports evaluation and delivery failures; defaults to log.Printf
+	Logf func(format string, args ...interface{})
+	// StatePath is a JSON file keeping cooldowns and the metric values already
+	// alerted on between runs, e.g. of single evaluations from cron. Without
+	// it they only hold within one engine.
+	StatePath string
+}
+
+// alertObservation is a value to check against a rule
+type alertObservation struct {
+	objectID    string
+	metric      string
+	value       float64
+	baseline    []float64
+	observedAt  time.Time
+	fingerprint string
+}
+
+// commentHistory tracks the comment count of one post between evaluations
+type commentHistory struct {
+	count int
+	at    time.Time
+	rates []float64
+}
+
+// firedAlert remembers the last notification of a rule for an object
+type firedAlert struct {
+	At          time.Time `json:"at"`
+	Fingerprint string    `json:"fingerprint,omitempty"`
+}
+
+// AlertEngine periodically evaluates alert rules and notifies on the ones
+// that fire. Baselines of comment volume are kept in memory, so comment
+// volume rules need one evaluation before they can fire. Cooldowns are kept
+// in memory too, unless AlertConfig.StatePath is set.
+type AlertEngine struct {
+	client *Client
+	config AlertConfig
+
+	mu          sync.Mutex
+	comments    map[string]*commentHistory
+	fired       map[string]firedAlert
+	stateLoaded bool
+	now         func() time.Time
+}
+
+// NewAlertEngine creates an engine evaluating config.Rules with client
+func NewAlertEngine(client *Client, config AlertConfig) *AlertEngine {
+	if config.Interval <= 0 {
+		config.Interval = 5 * time.Minute
+	}
+	if config.Logf == nil {
+		config.Logf = log.Printf
+	}
+
+	return &AlertEngine{
+		client:   client,
+		config:   config,
+		comments: make(map[string]*commentHistory),
+		fired:    make(map[string]firedAlert),
+		now:      time.Now,
+	}
+}
+
+// Run evaluates immediately and then every Interval until ctx is done
+func (e *AlertEngine) Run(ctx context.Context) error {
+	ticker := time.NewTicker(e.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := e.Evaluate(ctx); err != nil {
+			e.config.Logf("alerts: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Evaluate checks every rule once and notifies on alerts that are not in
+// cooldown. It returns the alerts delivered; the error joins rule and
+// delivery failures, which do not stop other rules.
+func (e *AlertEngine) Evaluate(ctx context.Context) ([]Alert, error) {
+	if err := e.loadState(); err != nil {
+		return nil, err
+	}
+	client := e.client.WithContext(ctx)
+
+	var alerts []Alert
+	var errs []error
+	for i := range e.config.Rules {
+		if err := ctx.Err(); err != nil {
+			if saveErr := e.saveState(); saveErr != nil {
+				return alerts, errors.Join(err, saveErr)
+			}
+			return alerts, err
+		}
+		rule := &e.config.Rules[i]
+
+		var observations []alertObservation
+		var err error
+		switch rule.Source {
+		case AlertSourcePageMetric:
+			observations, err = e.observePageMetric(client, rule)
+		case AlertSourceCommentVolume:
+			observations, err = e.observeCommentVolume(client, rule)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("rule %s: %w", rule.Name, err))
+			continue
+		}
+
+		for _, observation := range observations {
+			alert, ok := evaluateAlertRule(rule, observation)
+			if !ok || e.suppressed(rule, observation) {
+				continue
+			}
+			alert.FiredAt = e.now().UTC()
+			alert.ID = fmt.Sprintf("%s:%s:%d", rule.Name, observation.objectID, alert.FiredAt.Unix())
+
+			if err := e.notify(ctx, alert); err != nil {
+				errs = append(errs, fmt.Errorf("rule %s: %w", rule.Name, err))
+				continue
+			}
+			e.markFired(rule, observation, alert.FiredAt)
+			e.client.Metrics.AddCounter("facebook_alerts_fired_total", "Alerts delivered, by rule",
+				Labels{"rule": rule.Name, "severity": rule.Severity}, 1)
+			alerts = append(alerts, alert)
+		}
+	}
+
+	if err := e.saveState(); err != nil {
+		errs = append(errs, err)
+	}
+	return alerts, errors.Join(errs...)
+}
+
+// observePageMetric reads the latest daily value of the rule's metric and
+// the days before it as the baseline
+func (e *AlertEngine) observePageMetric(client *Client, rule *AlertRule) ([]alertObservation, error) {
+	baseline := rule.Baseline
+	if baseline <= 0 {
+		baseline = defaultAlertBaseline
+	}
+
+	// Daily values are published with a delay, so look back a few extra days
+	until := e.now().UTC()
+	since := until.AddDate(0, 0, -(baseline + 3))
+	insights, err := client.GetPageInsights(rule.PageID, []string{rule.Metric}, PeriodDay, &since, &until)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []Value
+	for _, insight := range insights.Data {
+		if insight.Name != rule.Metric || insight.Period != PeriodDay {
+			continue
+		}
+		for _, value := range insight.Values {
+			if value.Value != nil {
+				values = append(values, value)
+			}
+		}
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+	sort.SliceStable(values, func(i, j int) bool {
+		return values[i].EndTime.Before(values[j].EndTime.Time)
+	})
+
+	latest := values[len(values)-1]
+	history := make([]float64, 0, baseline)
+	for _, value := range values[:len(values)-1] {
+		history = append(history, value.Float())
+	}
+	if len(history) > baseline {
+		history = history[len(history)-baseline:]
+	}
+
+	return []alertObservation{{
+		objectID:   rule.PageID,
+		metric:     rule.Metric,
+		value:      latest.Float(),
+		baseline:   history,
+		observedAt: latest.EndTime.UTC(),
+		// A day's value alerts once, however often it is re-read
+		fingerprint: latest.EndTime.UTC().Format(time.RFC3339),
+	}}, nil
+}
+
+// observeCommentVolume compares the comment totals of recent posts with the
+// previous evaluation and returns the new comments per hour of each post
+func (e *AlertEngine) observeCommentVolume(client *Client, rule *AlertRule) ([]alertObservation, error) {
+	recent := rule.RecentPosts
+	if recent <= 0 {
+		recent = defaultAlertRecentPosts
+	}
+	baseline := rule.Baseline
+	if baseline <= 0 {
+		baseline = defaultAlertBaseline
+	}
+
+	posts, err := client.GetPosts(rule.PageID, recent, "id", "created_time", "comments.summary(total_count).limit(0)")
+	if err != nil {
+		return nil, err
+	}
+
+	now := e.now().UTC()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	prefix := rule.Name + "|"
+	seen := make(map[string]bool, len(posts.Data))
+
+	var observations []alertObservation
+	for _, post := range posts.Data {
+		count := 0
+		if post.Comments != nil {
+			count = post.Comments.Summary.TotalCount
+		}
+
+		key := prefix + post.ID
+		seen[key] = true
+		history, ok := e.comments[key]
+		if !ok {
+			e.comments[key] = &commentHistory{count: count, at: now}
+			continue
+		}
+
+		hours := now.Sub(history.at).Hours()
+		if hours <= 0 {
+			continue
+		}
+		// Deleted comments lower the total; they are not negative volume
+		rate := math.Max(float64(count-history.count), 0) / hours
+
+		observations = append(observations, alertObservation{
+			objectID:   post.ID,
+			metric:     commentVolumeMetric,
+			value:      rate,
+			baseline:   append([]float64(nil), history.rates...),
+			observedAt: now,
+		})
+
+		history.count = count
+		history.at = now
+		history.rates = append(history.rates, rate)
+		if len(history.rates) > baseline {
+			history.rates = history.rates[len(history.rates)-baseline:]
+		}
+	}
+
+	// Posts that dropped out of the recent ones are no longer watched
+	for key := range e.comments {
+		if strings.HasPrefix(key, prefix) && !seen[key] {
+			delete(e.comments, key)
+		}
+	}
+	for key := range e.fired {
+		if strings.HasPrefix(key, prefix) && !seen[key] {
+			delete(e.fired, key)
+		}
+	}
+	return observations, nil
+}
+
+// evaluateAlertRule checks an observation against the conditions of a rule.
+// The standard deviation of the baseline is floored at 1, as values are
+// counts: a flat baseline still fires on a large jump but not on noise.
+func evaluateAlertRule(rule *AlertRule, observation alertObservation) (Alert, bool) {
+	alert := Alert{
+		Rule:       rule.Name,
+		Severity:   rule.Severity,
+		PageID:     rule.PageID,
+		ObjectID:   observation.objectID,
+		Metric:     observation.metric,
+		Value:      observation.value,
+		ObservedAt: observation.observedAt,
+	}
+
+	value := observation.value
+	if rule.Above != nil && value > *rule.Above {
+		alert.Conditions = append(alert.Conditions, "above "+strconv.FormatFloat(*rule.Above, 'f', -1, 64))
+	}
+	if rule.Below != nil && value < *rule.Below {
+		alert.Conditions = append(alert.Conditions, "below "+strconv.FormatFloat(*rule.Below, 'f', -1, 64))
+	}
+
+	if len(observation.baseline) >= minBaselineSamples {
+		mean, stddev := meanStdDev(observation.baseline)
+		z := (value - mean) / math.Max(stddev, 1)
+		alert.Mean, alert.StdDev, alert.ZScore = mean, stddev, &z
+
+		if (rule.ZScore > 0 && z >= rule.ZScore) || (rule.ZScore < 0 && z <= rule.ZScore) {
+			alert.Conditions = append(alert.Conditions, fmt.Sprintf("z-score %.1f beyond %s", z, strconv.FormatFloat(rule.ZScore, 'f', -1, 64)))
+		}
+	}
+
+	return alert, len(alert.Conditions) > 0
+}
+
+// meanStdDev returns the mean and population standard deviation of values
+func meanStdDev(values []float64) (float64, float64) {
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(variance / float64(len(values)))
+}
+
+// suppressed reports whether the rule already fired for the object within
+// its cooldown or for the same observation
+func (e *AlertEngine) suppressed(rule *AlertRule, observation alertObservation) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	last, ok := e.fired[rule.Name+"|"+observation.objectID]
+	if !ok {
+		return false
+	}
+	if observation.fingerprint != "" && observation.fingerprint == last.Fingerprint {
+		return true
+	}
+
+	cooldown := time.Duration(rule.Cooldown)
+	if cooldown <= 0 {
+		cooldown = defaultAlertCooldown
+	}
+	return e.now().Sub(last.At) < cooldown
+}
+
+// markFired starts the cooldown of the rule for the object
+func (e *AlertEngine) markFired(rule *AlertRule, observation alertObservation, at time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.fired[rule.Name+"|"+observation.objectID] = firedAlert{At: at, Fingerprint: observation.fingerprint}
+}
+
+// loadState reads the cooldowns of StatePath before the first evaluation.
+// A missing file is an empty state.
+func (e *AlertEngine) loadState() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.config.StatePath == "" || e.stateLoaded {
+		return nil
+	}
+
+	data, err := os.ReadFile(e.config.StatePath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("reading alert state: %w", err)
+	}
+	if err == nil {
+		var fired map[string]firedAlert
+		if err := json.Unmarshal(data, &fired); err != nil {
+			return fmt.Errorf("parsing alert state: %w", err)
+		}
+		for key, last := range fired {
+			e.fired[key] = last
+		}
+	}
+
+	e.stateLoaded = true
+	return nil
+}
+
+// saveState atomically replaces StatePath with the current cooldowns
+func (e *AlertEngine) saveState() error {
+	if e.config.StatePath == "" {
+		return nil
+	}
+
+	e.mu.Lock()
+	data, err := json.MarshalIndent(e.fired, "", "  ")
+	e.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("encoding alert state: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(e.config.StatePath), filepath.Base(e.config.StatePath)+".*")
+	if err != nil {
+		return fmt.Errorf("creating alert state temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing alert state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing alert state temp file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), e.config.StatePath); err != nil {
+		return fmt.Errorf("replacing alert state: %w", err)
+	}
+	return nil
+}
+
+// notify delivers an alert to every notifier. The alert counts as delivered
+// when any notifier succeeds; otherwise it is retried on the next evaluation.
+func (e *AlertEngine) notify(ctx context.Context, alert Alert) error {
+	if len(e.config.Notifiers) == 0 {
+		e.config.Logf("alert: %s", alert.Summary())
+		return nil
+	}
+
+	var errs []error
+	for _, notifier := range e.config.Notifiers {
+		if err := notifier.Notify(ctx, alert); err != nil {
+			e.config.Logf("alerts: %s failed to deliver %s: %v", notifier.Name(), alert.ID, err)
+			errs = append(errs, fmt.Errorf("%s: %w", notifier.Name(), err))
+		}
+	}
+	if len(errs) == len(e.config.Notifiers) {
+		return errors.Join(errs...)
+	}
+	return nil
+}
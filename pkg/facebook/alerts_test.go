@@ -0,0 +1,243 @@
+package facebook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingNotifier collects delivered alerts
+type recordingNotifier struct {
+	mu     sync.Mutex
+	alerts []Alert
+}
+
+func (n *recordingNotifier) Name() string { return "recording" }
+
+func (n *recordingNotifier) Notify(ctx context.Context, alert Alert) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.alerts = append(n.alerts, alert)
+	return nil
+}
+
+func TestAlertEngineCommentVolumeZScoreAndCooldown(t *testing.T) {
+	var mu sync.Mutex
+	comments := 10
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Fprintf(w, `{"data":[{"id":"post1","created_time":"2024-03-04T09:00:00+0000","comments":{"data":[],"summary":{"total_count":%d}}}]}`, comments)
+	}))
+	defer server.Close()
+
+	client := NewClient("test_token")
+	client.BaseURL = server.URL
+
+	notifier := &recordingNotifier{}
+	engine := NewAlertEngine(client, AlertConfig{
+		Rules: []AlertRule{{
+			Name:     "comment-spike",
+			PageID:   "page",
+			Source:   AlertSourceCommentVolume,
+			ZScore:   3,
+			Cooldown: JSONDuration(2 * time.Hour),
+		}},
+		Notifiers: []AlertNotifier{notifier},
+	})
+
+	now := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	engine.now = func() time.Time { return now }
+
+	// Steady volume of 2 comments per hour builds the baseline, then 50
+	// arrive in an hour, and a larger spike follows within the cooldown
+	for _, added := range []int{0, 2, 2, 2, 2, 50, 200} {
+		mu.Lock()
+		comments += added
+		mu.Unlock()
+
+		if _, err := engine.Evaluate(context.Background()); err != nil {
+			t.Fatalf("Expected evaluation to succeed, got %v", err)
+		}
+		now = now.Add(time.Hour)
+	}
+
+	if len(notifier.alerts) != 1 {
+		t.Fatalf("Expected one alert after cooldown suppression, got %d", len(notifier.alerts))
+	}
+
+	alert := notifier.alerts[0]
+	if alert.ObjectID != "post1" || alert.Value != 50 || alert.Metric != commentVolumeMetric {
+		t.Errorf("Unexpected alert %+v", alert)
+	}
+	if alert.ZScore == nil || *alert.ZScore < 3 || alert.Mean != 2 {
+		t.Errorf("Expected a z-score of at least 3 over a mean of 2, got %+v", alert)
+	}
+}
+
+func TestEvaluateAlertRuleThresholds(t *testing.T) {
+	above := 100.0
+	rule := &AlertRule{Name: "unfollows", PageID: "page", Source: AlertSourcePageMetric, Metric: "page_daily_unfollows_unique", Above: &above}
+
+	if _, ok := evaluateAlertRule(rule, alertObservation{objectID: "page", value: 80}); ok {
+		t.Error("Expected a value below the threshold not to fire")
+	}
+
+	alert, ok := evaluateAlertRule(rule, alertObservation{objectID: "page", value: 120})
+	if !ok || len(alert.Conditions) != 1 || alert.Conditions[0] != "above 100" {
+		t.Errorf("Expected the threshold to fire, got %+v", alert)
+	}
+	if alert.ZScore != nil {
+		t.Errorf("Expected no z-score without a baseline, got %v", *alert.ZScore)
+	}
+}
+
+func TestAlertEngineForgetsPostsOutsideRecent(t *testing.T) {
+	var mu sync.Mutex
+	posts := []string{"post1", "post2"}
+	comments := 0
+
+	// Every post gains a comment between requests
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		comments++
+		var data []string
+		for _, id := range posts {
+			data = append(data, fmt.Sprintf(`{"id":%q,"comments":{"data":[],"summary":{"total_count":%d}}}`, id, comments))
+		}
+		fmt.Fprintf(w, `{"data":[%s]}`, strings.Join(data, ","))
+	}))
+	defer server.Close()
+
+	client := NewClient("test_token")
+	client.BaseURL = server.URL
+
+	engine := NewAlertEngine(client, AlertConfig{
+		Rules: []AlertRule{
+			{Name: "comment-spike", PageID: "page", Source: AlertSourceCommentVolume, RecentPosts: 2, ZScore: 3},
+			{Name: "comment-flood", PageID: "page", Source: AlertSourceCommentVolume, RecentPosts: 2, Above: new(float64)},
+		},
+		Notifiers: []AlertNotifier{&recordingNotifier{}},
+	})
+	now := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	engine.now = func() time.Time { return now }
+
+	// The first evaluation builds the history, the second fires the flood rule
+	for i := 0; i < 2; i++ {
+		if _, err := engine.Evaluate(context.Background()); err != nil {
+			t.Fatalf("Expected evaluation to succeed, got %v", err)
+		}
+		now = now.Add(time.Hour)
+	}
+	if len(engine.comments) != 4 || len(engine.fired) != 2 {
+		t.Fatalf("Expected history for two posts of two rules and two cooldowns, got %d and %d", len(engine.comments), len(engine.fired))
+	}
+
+	// A new post pushes post1 out of the two most recent
+	mu.Lock()
+	posts = []string{"post3", "post2"}
+	mu.Unlock()
+
+	if _, err := engine.Evaluate(context.Background()); err != nil {
+		t.Fatalf("Expected evaluation to succeed, got %v", err)
+	}
+	for _, key := range []string{"comment-spike|post1", "comment-flood|post1"} {
+		if _, ok := engine.comments[key]; ok {
+			t.Errorf("Expected the history of %s to be forgotten", key)
+		}
+		if _, ok := engine.fired[key]; ok {
+			t.Errorf("Expected the cooldown of %s to be forgotten", key)
+		}
+	}
+	if len(engine.comments) != 4 {
+		t.Errorf("Expected history for post2 and post3 of both rules, got %d", len(engine.comments))
+	}
+	if _, ok := engine.fired["comment-flood|post2"]; !ok {
+		t.Error("Expected the cooldown of post2 to be kept")
+	}
+}
+
+func TestLoadAlertRulesChecksMetrics(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	write := func(rules string) {
+		if err := os.WriteFile(path, []byte(rules), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`[{"name":"negative-feedback","page_id":"123","source":"page_metric","metric":"page_negative_feedback","above":10}]`)
+	_, err := LoadAlertRules(path, DefaultAPIVersion)
+	var metricErr *MetricError
+	if !errors.As(err, &metricErr) || len(metricErr.Problems) != 1 || !strings.HasPrefix(err.Error(), "alert rule negative-feedback: ") {
+		t.Errorf("Expected a deprecated metric error for the rule, got %v", err)
+	}
+
+	// The metric still exists before v18.0
+	if _, err := LoadAlertRules(path, "v17.0"); err != nil {
+		t.Errorf("Expected the rule to load for v17.0, got %v", err)
+	}
+
+	write(`[
+		{"name":"unfollows","page_id":"123","source":"page_metric","metric":"page_daily_unfollows_unique","above":10},
+		{"name":"comment-spike","page_id":"123","source":"comment_volume","zscore":4}]`)
+	rules, err := LoadAlertRules(path, DefaultAPIVersion)
+	if err != nil || len(rules) != 2 {
+		t.Errorf("Expected two rules to load, got %d (%v)", len(rules), err)
+	}
+}
+
+func TestAlertEngineKeepsCooldownsInStatePath(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, `{"data":[{"name":"page_impressions","period":"day","values":[
+			{"value":10,"end_time":"2024-03-03T08:00:00+0000"},
+			{"value":120,"end_time":"2024-03-04T08:00:00+0000"}]}]}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("test_token")
+	client.BaseURL = server.URL
+
+	above := 100.0
+	config := AlertConfig{
+		Rules:     []AlertRule{{Name: "impressions", PageID: "page", Source: AlertSourcePageMetric, Metric: "page_impressions", Above: &above}},
+		StatePath: filepath.Join(t.TempDir(), "alert-state.json"),
+	}
+	now := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+
+	// Each run uses a new engine, like single evaluations from cron
+	evaluate := func() []Alert {
+		notifier := &recordingNotifier{}
+		config.Notifiers = []AlertNotifier{notifier}
+		engine := NewAlertEngine(client, config)
+		engine.now = func() time.Time { return now }
+		if _, err := engine.Evaluate(context.Background()); err != nil {
+			t.Fatalf("Expected evaluation to succeed, got %v", err)
+		}
+		return notifier.alerts
+	}
+
+	if alerts := evaluate(); len(alerts) != 1 {
+		t.Fatalf("Expected the first run to alert, got %d alerts", len(alerts))
+	}
+
+	// The day's value was alerted on, even after the cooldown
+	now = now.Add(3 * time.Hour)
+	if alerts := evaluate(); len(alerts) != 0 {
+		t.Errorf("Expected the next run not to repeat the alert, got %+v", alerts)
+	}
+
+	config.StatePath = filepath.Join(t.TempDir(), "other-state.json")
+	if alerts := evaluate(); len(alerts) != 1 {
+		t.Errorf("Expected a run with fresh state to alert again, got %d alerts", len(alerts))
+	}
+}
@@ -0,0 +1,390 @@
+package facebook
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults and bounds of AnalyticsOptions
+const (
+	DefaultAnalyticsLimit = 25
+	MaxAnalyticsLimit     = 100
+)
+
+// commentVelocityWindow is the time after publishing over which comment
+// velocity is measured
+const commentVelocityWindow = 24 * time.Hour
+
+// maxVelocityComments bounds the comments read per post for comment velocity
+const maxVelocityComments = 100
+
+// analyticsPostFields are requested for each analysed post, so reaction,
+// comment and share totals come with the post list
+var analyticsPostFields = []string{
+	"id", "message", "created_time", "permalink_url", "shares",
+	"reactions.summary(total_count).limit(0)",
+	"comments.summary(total_count).limit(0)",
+}
+
+// analyticsPostMetrics are the post insights an analytics report uses
+var analyticsPostMetrics = []string{
+	"post_impressions",
+	"post_impressions_unique",
+	"post_clicks",
+	"post_reactions_by_type_total",
+}
+
+// AnalyticsOptions tunes GetPageAnalytics
+type AnalyticsOptions struct {
+	// Limit is the number of latest posts analysed (default 25, at most 100)
+	Limit int
+	// Location is the time zone of the hour-of-week heatmap (default UTC)
+	Location *time.Location
+	// Concurrency is the number of posts fetched at once (default 4)
+	Concurrency int
+}
+
+// ParseAnalyticsOptions parses the limit and tz parameters of an analytics
+// request; tz is an IANA time zone name such as Europe/Berlin
+func ParseAnalyticsOptions(query url.Values) (*AnalyticsOptions, error) {
+	opts := &AnalyticsOptions{}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 || limit > MaxAnalyticsLimit {
+			return nil, &InsightsQueryError{
+				Param:  "limit",
+				Value:  value,
+				Reason: fmt.Sprintf("must be between 1 and %d", MaxAnalyticsLimit),
+			}
+		}
+		opts.Limit = limit
+	}
+
+	if value := query.Get("tz"); value != "" {
+		location, err := time.LoadLocation(value)
+		if err != nil {
+			return nil, &InsightsQueryError{Param: "tz", Value: value, Reason: "unknown time zone"}
+		}
+		opts.Location = location
+	}
+
+	return opts, nil
+}
+
+// PostAnalytics is the performance of one post
+type PostAnalytics struct {
+	PostID      string    `json:"post_id"`
+	Message     string    `json:"message,omitempty"`
+	Permalink   string    `json:"permalink_url,omitempty"`
+	CreatedTime time.Time `json:"created_time"`
+	Impressions int       `json:"impressions"`
+	Reach       int       `json:"reach"`
+	Clicks      int       `json:"clicks"`
+	Reactions   int       `json:"reactions"`
+	Comments    int       `json:"comments"`
+	Shares      int       `json:"shares"`
+	// Engagements is the sum of reactions, comments and shares
+	Engagements int `json:"engagements"`
+	// EngagementRate is engagements per person reached
+	EngagementRate float64 `json:"engagement_rate"`
+	// ReactionMix is the share of each reaction type, summing to 1
+	ReactionMix map[string]float64 `json:"reaction_mix,omitempty"`
+	// CommentVelocity is comments per hour during the first 24 hours
+	CommentVelocity float64 `json:"comment_velocity"`
+	// Error is set when insights or comments of the post could not be read;
+	// the remaining fields hold what was available
+	Error string `json:"error,omitempty"`
+}
+
+// HeatmapCell aggregates the posts published in one hour of the week
+type HeatmapCell struct {
+	Weekday           string  `json:"weekday"`
+	Hour              int     `json:"hour"`
+	Posts             int     `json:"posts"`
+	AvgReach          float64 `json:"avg_reach"`
+	AvgEngagementRate float64 `json:"avg_engagement_rate"`
+}
+
+// AnalyticsSummary aggregates the analysed posts
+type AnalyticsSummary struct {
+	Posts              int                `json:"posts"`
+	Reach              int                `json:"reach"`
+	Engagements        int                `json:"engagements"`
+	AvgEngagementRate  float64            `json:"avg_engagement_rate"`
+	AvgCommentVelocity float64            `json:"avg_comment_velocity"`
+	ReactionMix        map[string]float64 `json:"reaction_mix,omitempty"`
+	// Incomplete counts posts with Error set. They are included in the
+	// totals but left out of the averages and the heatmap, as their missing
+	// reach or comments would read as zero engagement.
+	Incomplete int `json:"incomplete,omitempty"`
+	// BestTimes are the heatmap cells with the highest average engagement rate
+	BestTimes []HeatmapCell `json:"best_times,omitempty"`
+}
+
+// PageAnalytics is the post performance report of a page
+type PageAnalytics struct {
+	PageID      string           `json:"page_id"`
+	Timezone    string           `json:"timezone"`
+	GeneratedAt time.Time        `json:"generated_at"`
+	Summary     AnalyticsSummary `json:"summary"`
+	Posts       []PostAnalytics  `json:"posts"`
+	// Heatmap has 168 cells, Sunday 00:00 first, in the report's time zone
+	Heatmap []HeatmapCell `json:"heatmap"`
+}
+
+// GetPageAnalytics analyses the latest posts of a page. It combines GetPosts,
+// post insights and comment timestamps into per-post engagement rate, reach,
+// reaction mix and comment velocity, and an hour-of-week heatmap. A post whose
+// insights or comments fail is reported with its Error set rather than
+// failing the report.
+func (c *Client) GetPageAnalytics(ctx context.Context, pageID string, opts *AnalyticsOptions) (*PageAnalytics, error) {
+	if opts == nil {
+		opts = &AnalyticsOptions{}
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = DefaultAnalyticsLimit
+	}
+	if limit > MaxAnalyticsLimit {
+		limit = MaxAnalyticsLimit
+	}
+	location := opts.Location
+	if location == nil {
+		location = time.UTC
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	client := c.WithContext(ctx)
+	posts, err := client.GetPosts(pageID, limit, analyticsPostFields...)
+	if err != nil {
+		return nil, fmt.Errorf("analysing page %s: %w", pageID, err)
+	}
+
+	results := make([]PostAnalytics, len(posts.Data))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+
+	for i, post := range posts.Data {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, post Post) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			results[i] = client.analysePost(post)
+		}(i, post)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return BuildPageAnalytics(pageID, results, location), nil
+}
+
+// analysePost fetches the insights and early comments of a post
+func (c *Client) analysePost(post Post) PostAnalytics {
+	result := PostAnalytics{
+		PostID:      post.ID,
+		Message:     post.Message,
+		Permalink:   post.Permalink,
+		CreatedTime: post.CreatedTime.Time,
+	}
+	if post.Reactions != nil {
+		result.Reactions = post.Reactions.Summary.TotalCount
+	}
+	if post.Comments != nil {
+		result.Comments = post.Comments.Summary.TotalCount
+	}
+	if post.Shares != nil {
+		result.Shares = post.Shares.Count
+	}
+
+	var errs []string
+	insights, err := c.GetPostInsights(post.ID, analyticsPostMetrics)
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("insights: %v", err))
+	} else {
+		applyPostInsights(&result, insights)
+	}
+
+	if result.Comments > 0 {
+		comments, err := c.GetPostComments(post.ID, maxVelocityComments, "chronological", "id", "created_time")
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("comments: %v", err))
+		} else {
+			result.CommentVelocity = commentVelocity(result.CreatedTime, comments.Data, time.Now())
+		}
+	}
+
+	if len(errs) > 0 {
+		result.Error = strings.Join(errs, "; ")
+	}
+	finishPostAnalytics(&result)
+	return result
+}
+
+// applyPostInsights copies the lifetime values of post insights into result
+func applyPostInsights(result *PostAnalytics, insights *InsightsResponse) {
+	for _, insight := range insights.Data {
+		if len(insight.Values) == 0 {
+			continue
+		}
+		value := insight.Values[len(insight.Values)-1]
+
+		switch insight.Name {
+		case "post_impressions":
+			result.Impressions = int(value.Float())
+		case "post_impressions_unique":
+			result.Reach = int(value.Float())
+		case "post_clicks":
+			result.Clicks = int(value.Float())
+		case "post_reactions_by_type_total":
+			breakdown := value.Breakdown()
+			result.ReactionMix = normalizeMix(breakdown)
+			// The summary edge may be missing when the token lacks
+			// permission to read reactions; fall back to the breakdown
+			if result.Reactions == 0 {
+				result.Reactions = int(value.Float())
+			}
+		}
+	}
+}
+
+// finishPostAnalytics derives engagements and engagement rate from the counts
+func finishPostAnalytics(result *PostAnalytics) {
+	result.Engagements = result.Reactions + result.Comments + result.Shares
+	if result.Reach > 0 {
+		result.EngagementRate = float64(result.Engagements) / float64(result.Reach)
+	}
+}
+
+// commentVelocity returns comments per hour over the first 24 hours after
+// created, or over the time elapsed until now for younger posts. comments
+// must be in chronological order; only the first page is considered, so
+// velocity saturates at maxVelocityComments per window.
+func commentVelocity(created time.Time, comments []Comment, now time.Time) float64 {
+	window := commentVelocityWindow
+	if elapsed := now.Sub(created); elapsed < window {
+		window = elapsed
+	}
+	if window < time.Hour {
+		window = time.Hour
+	}
+
+	end := created.Add(commentVelocityWindow)
+	count := 0
+	for _, comment := range comments {
+		if comment.CreatedTime.Before(end) {
+			count++
+		}
+	}
+	return float64(count) / window.Hours()
+}
+
+// normalizeMix scales counts so they sum to 1
+func normalizeMix(counts map[string]float64) map[string]float64 {
+	total := 0.0
+	for _, n := range counts {
+		total += n
+	}
+	if total <= 0 {
+		return nil
+	}
+
+	mix := make(map[string]float64, len(counts))
+	for key, n := range counts {
+		mix[key] = n / total
+	}
+	return mix
+}
+
+// BuildPageAnalytics aggregates analysed posts into a report, bucketing posts
+// into the hour-of-week heatmap by their creation time in location. Posts
+// with Error set only count towards the totals.
+func BuildPageAnalytics(pageID string, posts []PostAnalytics, location *time.Location) *PageAnalytics {
+	report := &PageAnalytics{
+		PageID:      pageID,
+		Timezone:    location.String(),
+		GeneratedAt: time.Now().UTC(),
+		Posts:       posts,
+		Heatmap:     make([]HeatmapCell, 7*24),
+	}
+
+	for i := range report.Heatmap {
+		report.Heatmap[i].Weekday = time.Weekday(i / 24).String()
+		report.Heatmap[i].Hour = i % 24
+	}
+
+	summary := &report.Summary
+	reactions := make(map[string]float64)
+	for _, post := range posts {
+		if post.CreatedTime.IsZero() {
+			continue
+		}
+		summary.Posts++
+		summary.Reach += post.Reach
+		summary.Engagements += post.Engagements
+		for key, share := range post.ReactionMix {
+			reactions[key] += share * float64(post.Reactions)
+		}
+
+		if post.Error != "" {
+			summary.Incomplete++
+			continue
+		}
+		summary.AvgEngagementRate += post.EngagementRate
+		summary.AvgCommentVelocity += post.CommentVelocity
+
+		local := post.CreatedTime.In(location)
+		cell := &report.Heatmap[int(local.Weekday())*24+local.Hour()]
+		cell.Posts++
+		cell.AvgReach += float64(post.Reach)
+		cell.AvgEngagementRate += post.EngagementRate
+	}
+
+	if complete := summary.Posts - summary.Incomplete; complete > 0 {
+		summary.AvgEngagementRate /= float64(complete)
+		summary.AvgCommentVelocity /= float64(complete)
+	}
+	summary.ReactionMix = normalizeMix(reactions)
+
+	var active []HeatmapCell
+	for i := range report.Heatmap {
+		cell := &report.Heatmap[i]
+		if cell.Posts == 0 {
+			continue
+		}
+		cell.AvgReach /= float64(cell.Posts)
+		cell.AvgEngagementRate /= float64(cell.Posts)
+		active = append(active, *cell)
+	}
+
+	sort.SliceStable(active, func(i, j int) bool {
+		return active[i].AvgEngagementRate > active[j].AvgEngagementRate
+	})
+	if len(active) > 3 {
+		active = active[:3]
+	}
+	summary.BestTimes = active
+
+	return report
+}
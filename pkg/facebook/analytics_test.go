@@ -0,0 +1,98 @@
+package facebook
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetPageAnalytics(t *testing.T) {
+	// Two posts: one on a Monday at 09:00 UTC with reach and reactions, one
+	// without comments, whose comments must then not be requested
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case strings.HasSuffix(req.URL.Path, "/page/posts"):
+			fmt.Fprint(w, `{"data":[
+				{"id":"p1","message":"hello","created_time":"2024-03-04T09:30:00+0000",
+				 "shares":{"count":5},"reactions":{"data":[],"summary":{"total_count":30}},
+				 "comments":{"data":[],"summary":{"total_count":3}}},
+				{"id":"p2","created_time":"2024-03-05T18:00:00+0000",
+				 "reactions":{"data":[],"summary":{"total_count":0}},
+				 "comments":{"data":[],"summary":{"total_count":0}}}]}`)
+		case strings.HasSuffix(req.URL.Path, "/p1/insights"):
+			fmt.Fprint(w, `{"data":[
+				{"name":"post_impressions_unique","period":"lifetime","values":[{"value":100}]},
+				{"name":"post_reactions_by_type_total","period":"lifetime","values":[{"value":{"like":20,"love":10}}]}]}`)
+		case strings.HasSuffix(req.URL.Path, "/p2/insights"):
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":{"message":"unsupported","code":100}}`)
+		case strings.HasSuffix(req.URL.Path, "/p1/comments"):
+			if req.URL.Query().Get("order") != "chronological" {
+				t.Errorf("Expected chronological comments, got %q", req.URL.Query().Get("order"))
+			}
+			fmt.Fprint(w, `{"data":[
+				{"id":"c1","created_time":"2024-03-04T10:00:00+0000"},
+				{"id":"c2","created_time":"2024-03-04T20:00:00+0000"},
+				{"id":"c3","created_time":"2024-03-06T10:00:00+0000"}]}`)
+		default:
+			t.Errorf("Unexpected request %s", req.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test_token")
+	client.BaseURL = server.URL
+
+	report, err := client.GetPageAnalytics(context.Background(), "page", nil)
+	if err != nil {
+		t.Fatalf("Expected analytics to succeed, got %v", err)
+	}
+	if len(report.Posts) != 2 {
+		t.Fatalf("Expected 2 posts, got %d", len(report.Posts))
+	}
+
+	p1 := report.Posts[0]
+	if p1.Reach != 100 || p1.Engagements != 38 || math.Abs(p1.EngagementRate-0.38) > 1e-9 {
+		t.Errorf("Unexpected p1 counts: reach %d, engagements %d, rate %v", p1.Reach, p1.Engagements, p1.EngagementRate)
+	}
+	if math.Abs(p1.ReactionMix["like"]-2.0/3) > 1e-9 {
+		t.Errorf("Expected like share 2/3, got %v", p1.ReactionMix)
+	}
+	// Two of three comments fall within the first 24 hours
+	if math.Abs(p1.CommentVelocity-2.0/24) > 1e-9 {
+		t.Errorf("Expected comment velocity 2/24, got %v", p1.CommentVelocity)
+	}
+
+	if report.Posts[1].Error == "" {
+		t.Error("Expected failed insights to be reported on the post")
+	}
+
+	// The failed post counts towards the totals but not the averages
+	summary := report.Summary
+	if summary.Posts != 2 || summary.Incomplete != 1 || summary.Engagements != 38 {
+		t.Errorf("Unexpected summary totals %+v", summary)
+	}
+	if math.Abs(summary.AvgEngagementRate-0.38) > 1e-9 || math.Abs(summary.AvgCommentVelocity-2.0/24) > 1e-9 {
+		t.Errorf("Expected averages of the complete post only, got %+v", summary)
+	}
+	if cell := report.Heatmap[int(time.Tuesday)*24+18]; cell.Posts != 0 {
+		t.Errorf("Expected the failed post to be left out of the heatmap, got %+v", cell)
+	}
+	if len(summary.BestTimes) != 1 {
+		t.Errorf("Expected only the complete post's hour in best times, got %+v", summary.BestTimes)
+	}
+
+	cell := report.Heatmap[int(time.Monday)*24+9]
+	if cell.Weekday != "Monday" || cell.Posts != 1 || math.Abs(cell.AvgEngagementRate-0.38) > 1e-9 {
+		t.Errorf("Unexpected Monday 09:00 cell: %+v", cell)
+	}
+	if len(report.Summary.BestTimes) == 0 || report.Summary.BestTimes[0].Hour != 9 {
+		t.Errorf("Expected Monday 09:00 to be the best time, got %+v", report.Summary.BestTimes)
+	}
+}
@@ -0,0 +1,396 @@
+package facebook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// BackupManifestName is the file name of the manifest written into the backup directory
+const BackupManifestName = "manifest.json"
+
+// backupPhotoFields are requested for every photo in a backup
+var backupPhotoFields = []string{
+	"id", "name", "created_time", "updated_time", "link", "width", "height", "images", "album",
+}
+
+// BackupOptions controls a photo library backup
+type BackupOptions struct {
+	// Dir is the backup directory; it is created if missing
+	Dir string
+	// PageSize is the number of albums or photos requested per page (default 100)
+	PageSize int
+	// Logf reports progress; defaults to log.Printf
+	Logf func(format string, args ...interface{})
+}
+
+// BackupManifest describes the contents of a backup directory
+type BackupManifest struct {
+	PageID    string        `json:"page_id"`
+	UpdatedAt time.Time     `json:"updated_at"`
+	Albums    []Album       `json:"albums"`
+	Photos    []BackupEntry `json:"photos"`
+}
+
+// BackupEntry is a downloaded photo and its metadata
+type BackupEntry struct {
+	Photo
+	AlbumID      string    `json:"album_id"`
+	AlbumName    string    `json:"album_name,omitempty"`
+	File         string    `json:"file"`
+	Bytes        int64     `json:"bytes"`
+	DownloadedAt time.Time `json:"downloaded_at"`
+}
+
+// BackupResult summarizes a backup run
+type BackupResult struct {
+	Albums     int      `json:"albums"`
+	Downloaded int      `json:"downloaded"`
+	Skipped    int      `json:"skipped"`
+	Failed     int      `json:"failed"`
+	Errors     []string `json:"errors,omitempty"`
+}
+
+// LargestImage returns the URL of the highest-resolution variant of a photo
+func (p *Photo) LargestImage() string {
+	source, best := p.Source, 0
+	for _, image := range p.Images {
+		if area := image.Width * image.Height; image.Source != "" && area > best {
+			source, best = image.Source, area
+		}
+	}
+	return source
+}
+
+// pagePhotosAlbumID is the directory of page photos that report no album
+const pagePhotosAlbumID = "photos"
+
+// BackupPhotos downloads every photo of every album of a page, then the
+// photos the page uploaded (/{page}/photos?type=uploaded) that no album
+// listed, into opts.Dir and records their metadata in a manifest. Photos
+// already in the manifest whose files exist are skipped, so an interrupted
+// backup can be rerun to resume. Individual download failures are counted
+// in the result; the returned error reports listing or manifest failures.
+func (c *Client) BackupPhotos(ctx context.Context, pageID string, opts BackupOptions) (*BackupResult, error) {
+	if opts.Dir == "" {
+		return nil, fmt.Errorf("backup directory is required")
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = 100
+	}
+	if opts.Logf == nil {
+		opts.Logf = log.Printf
+	}
+
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating backup directory: %w", err)
+	}
+
+	manifest, err := LoadBackupManifest(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+	manifest.PageID = pageID
+
+	client := c.WithContext(ctx)
+	albums, err := client.allAlbums(ctx, pageID, opts.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	manifest.Albums = albums
+
+	backup := &photoBackup{
+		client:   client,
+		opts:     opts,
+		manifest: manifest,
+		done:     make(map[string]int, len(manifest.Photos)),
+		seen:     make(map[string]bool),
+		result:   &BackupResult{Albums: len(albums)},
+	}
+	for i, entry := range manifest.Photos {
+		backup.done[entry.ID] = i
+	}
+
+	for _, album := range albums {
+		album := album
+		err := backup.walk(ctx, "album "+album.ID,
+			func(after string) (*PhotosResponse, error) {
+				return client.GetAlbumPhotos(album.ID, opts.PageSize, after, backupPhotoFields...)
+			},
+			func(Photo) Album { return album })
+		if err != nil {
+			return backup.result, err
+		}
+
+		opts.Logf("backup: album %q done (%d downloaded, %d skipped so far)", album.Name, backup.result.Downloaded, backup.result.Skipped)
+	}
+
+	err = backup.walk(ctx, "page "+pageID,
+		func(after string) (*PhotosResponse, error) {
+			return client.getUploadedPhotos(pageID, opts.PageSize, after)
+		},
+		func(photo Photo) Album {
+			if photo.Album.ID != "" {
+				return photo.Album
+			}
+			return Album{ID: pagePhotosAlbumID}
+		})
+	if err != nil {
+		return backup.result, err
+	}
+
+	return backup.result, saveBackupManifest(opts.Dir, manifest)
+}
+
+// photoBackup is the state of a BackupPhotos run
+type photoBackup struct {
+	client   *Client
+	opts     BackupOptions
+	manifest *BackupManifest
+	// done indexes manifest.Photos by photo ID
+	done map[string]int
+	// seen holds photos handled in this run, which page photos repeat
+	seen   map[string]bool
+	result *BackupResult
+}
+
+// walk backs up every photo returned by list, following paging, into the
+// album chosen by albumOf. The manifest is saved after every page.
+func (b *photoBackup) walk(ctx context.Context, source string, list func(after string) (*PhotosResponse, error), albumOf func(Photo) Album) error {
+	after := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return errors.Join(err, saveBackupManifest(b.opts.Dir, b.manifest))
+		}
+
+		photos, err := list(after)
+		if err != nil {
+			return errors.Join(fmt.Errorf("listing photos of %s: %w", source, err),
+				saveBackupManifest(b.opts.Dir, b.manifest))
+		}
+
+		for _, photo := range photos.Data {
+			if b.seen[photo.ID] {
+				continue
+			}
+			b.seen[photo.ID] = true
+
+			if i, ok := b.done[photo.ID]; ok && fileExists(filepath.Join(b.opts.Dir, b.manifest.Photos[i].File)) {
+				b.result.Skipped++
+				continue
+			}
+
+			entry, err := b.client.downloadBackupPhoto(ctx, b.opts.Dir, albumOf(photo), photo)
+			if err != nil {
+				b.result.Failed++
+				b.result.Errors = append(b.result.Errors, fmt.Sprintf("%s: %v", photo.ID, err))
+				b.opts.Logf("backup: photo %s failed: %v", photo.ID, err)
+				continue
+			}
+
+			if i, ok := b.done[photo.ID]; ok {
+				b.manifest.Photos[i] = *entry
+			} else {
+				b.done[photo.ID] = len(b.manifest.Photos)
+				b.manifest.Photos = append(b.manifest.Photos, *entry)
+			}
+			b.result.Downloaded++
+		}
+
+		// Persist progress after every page so an interrupted run resumes here
+		if err := saveBackupManifest(b.opts.Dir, b.manifest); err != nil {
+			return err
+		}
+
+		if photos.Paging.Next == "" || photos.Paging.Cursors.After == "" {
+			return nil
+		}
+		after = photos.Paging.Cursors.After
+	}
+}
+
+// getUploadedPhotos lists one page of the photos uploaded by a page
+func (c *Client) getUploadedPhotos(pageID string, limit int, after string) (*PhotosResponse, error) {
+	params := url.Values{}
+	params.Set("type", "uploaded")
+	params.Set("limit", fmt.Sprintf("%d", limit))
+	params.Set("fields", strings.Join(backupPhotoFields, ","))
+
+	if after != "" {
+		params.Set("after", after)
+	}
+
+	endpoint := fmt.Sprintf("%s/photos", pageID)
+	resp, err := c.makeRequest("GET", endpoint, params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting photos: %w", err)
+	}
+
+	var photosResp PhotosResponse
+	if err := c.handleResponse(resp, &photosResp); err != nil {
+		return nil, err
+	}
+
+	return &photosResp, nil
+}
+
+// allAlbums follows paging to list every album of a page
+func (c *Client) allAlbums(ctx context.Context, pageID string, pageSize int) ([]Album, error) {
+	var albums []Album
+	after := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		resp, err := c.GetAlbums(pageID, pageSize, after)
+		if err != nil {
+			return nil, fmt.Errorf("listing albums: %w", err)
+		}
+		albums = append(albums, resp.Data...)
+
+		if resp.Paging.Next == "" || resp.Paging.Cursors.After == "" {
+			return albums, nil
+		}
+		after = resp.Paging.Cursors.After
+	}
+}
+
+// downloadBackupPhoto downloads the largest image of a photo to
+// <dir>/<album id>/<photo id><ext> through a temporary file
+func (c *Client) downloadBackupPhoto(ctx context.Context, dir string, album Album, photo Photo) (*BackupEntry, error) {
+	source := photo.LargestImage()
+	if source == "" {
+		return nil, fmt.Errorf("photo has no downloadable image")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", source, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	// Large originals can take longer than HTTPClient.Timeout; ctx bounds the download
+	resp, err := c.transferHTTPClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("downloading image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading image: status %d", resp.StatusCode)
+	}
+
+	albumDir := filepath.Join(dir, album.ID)
+	if err := os.MkdirAll(albumDir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating album directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(albumDir, photo.ID+".*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("creating temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, resp.Body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("writing image: %w", err)
+	}
+
+	file := filepath.Join(album.ID, photo.ID+imageExtension(resp.Header.Get("Content-Type")))
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, file)); err != nil {
+		return nil, fmt.Errorf("saving image: %w", err)
+	}
+
+	// The manifest keeps only the downloaded variant
+	photo.Images = nil
+	photo.Source = source
+
+	return &BackupEntry{
+		Photo:        photo,
+		AlbumID:      album.ID,
+		AlbumName:    album.Name,
+		File:         filepath.ToSlash(file),
+		Bytes:        written,
+		DownloadedAt: time.Now().UTC(),
+	}, nil
+}
+
+// imageExtension maps a downloaded image's content type to a file extension
+func imageExtension(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err == nil {
+		if ext, ok := imageExtensions[mediaType]; ok {
+			return ext
+		}
+	}
+	return ".jpg"
+}
+
+// LoadBackupManifest reads the manifest of a backup directory. A missing
+// manifest yields an empty one.
+func LoadBackupManifest(dir string) (*BackupManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, BackupManifestName))
+	if errors.Is(err, os.ErrNotExist) {
+		return &BackupManifest{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading backup manifest: %w", err)
+	}
+
+	var manifest BackupManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("parsing backup manifest: %w", err)
+	}
+
+	return &manifest, nil
+}
+
+// saveBackupManifest atomically replaces the manifest of a backup directory
+func saveBackupManifest(dir string, manifest *BackupManifest) error {
+	manifest.UpdatedAt = time.Now().UTC()
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding backup manifest: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, BackupManifestName+".*")
+	if err != nil {
+		return fmt.Errorf("creating manifest temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing backup manifest: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing manifest temp file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, BackupManifestName)); err != nil {
+		return fmt.Errorf("replacing backup manifest: %w", err)
+	}
+
+	return nil
+}
+
+// fileExists reports whether a regular file exists at path
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
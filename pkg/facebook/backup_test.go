@@ -0,0 +1,187 @@
+package facebook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakePhotoLibrary serves one album with two pages of photos, the page's
+// uploaded photos and the images themselves
+type fakePhotoLibrary struct {
+	server *httptest.Server
+
+	mu          sync.Mutex
+	failListing bool
+	failImages  map[string]bool
+	downloads   []string
+}
+
+func newFakePhotoLibrary(t *testing.T) *fakePhotoLibrary {
+	library := &fakePhotoLibrary{failImages: make(map[string]bool)}
+	library.server = httptest.NewServer(library)
+	t.Cleanup(library.server.Close)
+	return library
+}
+
+func (f *fakePhotoLibrary) photo(id, album string) string {
+	albumField := ""
+	if album != "" {
+		albumField = fmt.Sprintf(`,"album":{"id":%q}`, album)
+	}
+	return fmt.Sprintf(`{"id":%q,"images":[{"width":10,"height":10,"source":"%s/img/%s_small"},{"width":100,"height":100,"source":"%s/img/%s"}]%s}`,
+		id, f.server.URL, id, f.server.URL, id, albumField)
+}
+
+func (f *fakePhotoLibrary) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(req.URL.Path, "/"+DefaultAPIVersion+"/")
+	after := req.URL.Query().Get("after")
+	switch {
+	case strings.HasPrefix(path, "/img/"):
+		id := strings.TrimPrefix(path, "/img/")
+		if f.failImages[id] {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		f.downloads = append(f.downloads, id)
+		w.Header().Set("Content-Type", "image/png")
+		fmt.Fprintf(w, "image %s", id)
+	case path == "page_1/albums":
+		fmt.Fprint(w, `{"data":[{"id":"album_1","name":"Launch"}]}`)
+	case path == "album_1/photos" && after == "":
+		fmt.Fprintf(w, `{"data":[%s],"paging":{"cursors":{"after":"p2"},"next":"more"}}`, f.photo("p1", "album_1"))
+	case path == "album_1/photos" && f.failListing:
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{"error":{"message":"Service unavailable","type":"OAuthException","code":2}}`)
+	case path == "album_1/photos":
+		fmt.Fprintf(w, `{"data":[%s]}`, f.photo("p2", "album_1"))
+	case path == "page_1/photos" && req.URL.Query().Get("type") == "uploaded":
+		fmt.Fprintf(w, `{"data":[%s,%s]}`, f.photo("p1", "album_1"), f.photo("p3", ""))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakePhotoLibrary) takeDownloads() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	downloads := f.downloads
+	f.downloads = nil
+	return downloads
+}
+
+func TestBackupPhotosWritesManifestAndSkipsExisting(t *testing.T) {
+	library := newFakePhotoLibrary(t)
+	client := NewClient("test_token")
+	client.BaseURL = library.server.URL
+
+	dir := t.TempDir()
+	opts := BackupOptions{Dir: dir, Logf: t.Logf}
+
+	result, err := client.BackupPhotos(context.Background(), "page_1", opts)
+	if err != nil {
+		t.Fatalf("Expected backup to succeed, got %v", err)
+	}
+	if result.Albums != 1 || result.Downloaded != 3 || result.Skipped != 0 || result.Failed != 0 {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	if got := strings.Join(library.takeDownloads(), ","); got != "p1,p2,p3" {
+		t.Errorf("Expected the largest image of each photo to be downloaded once, got %s", got)
+	}
+
+	manifest, err := LoadBackupManifest(dir)
+	if err != nil {
+		t.Fatalf("Expected manifest to load, got %v", err)
+	}
+	if manifest.PageID != "page_1" || len(manifest.Albums) != 1 || len(manifest.Photos) != 3 {
+		t.Fatalf("Unexpected manifest %+v", manifest)
+	}
+	files := map[string]string{"p1": "album_1/p1.png", "p2": "album_1/p2.png", "p3": pagePhotosAlbumID + "/p3.png"}
+	for _, entry := range manifest.Photos {
+		if entry.File != files[entry.ID] {
+			t.Errorf("Expected %s in %s, got %s", entry.ID, files[entry.ID], entry.File)
+		}
+		if entry.Images != nil || !strings.HasSuffix(entry.Source, "/img/"+entry.ID) {
+			t.Errorf("Expected only the downloaded variant in the manifest, got %+v", entry.Photo)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.File))
+		if err != nil || string(data) != "image "+entry.ID || entry.Bytes != int64(len(data)) {
+			t.Errorf("Unexpected file for %s: %q %v", entry.ID, data, err)
+		}
+	}
+
+	// A rerun skips everything on disk and downloads photos whose file is gone
+	if err := os.Remove(filepath.Join(dir, "album_1", "p2.png")); err != nil {
+		t.Fatal(err)
+	}
+	result, err = client.BackupPhotos(context.Background(), "page_1", opts)
+	if err != nil {
+		t.Fatalf("Expected rerun to succeed, got %v", err)
+	}
+	if result.Downloaded != 1 || result.Skipped != 2 {
+		t.Errorf("Expected one download and two skips, got %+v", result)
+	}
+	if got := strings.Join(library.takeDownloads(), ","); got != "p2" {
+		t.Errorf("Expected only the missing photo to be downloaded, got %s", got)
+	}
+	if manifest, _ := LoadBackupManifest(dir); len(manifest.Photos) != 3 {
+		t.Errorf("Expected the manifest entry to be replaced, got %d entries", len(manifest.Photos))
+	}
+}
+
+func TestBackupPhotosResumesAfterFailures(t *testing.T) {
+	library := newFakePhotoLibrary(t)
+	library.failListing = true
+	library.failImages["p1"] = true
+
+	client := NewClient("test_token")
+	client.BaseURL = library.server.URL
+
+	dir := t.TempDir()
+	opts := BackupOptions{Dir: dir, Logf: t.Logf}
+
+	// The first photo fails to download and the second page fails to list
+	result, err := client.BackupPhotos(context.Background(), "page_1", opts)
+	if err == nil || !strings.Contains(err.Error(), "listing photos of album album_1") {
+		t.Fatalf("Expected a listing error, got %v", err)
+	}
+	if result.Failed != 1 || len(result.Errors) != 1 || !strings.HasPrefix(result.Errors[0], "p1: ") {
+		t.Errorf("Expected the failed download to be reported, got %+v", result)
+	}
+	if manifest, _ := LoadBackupManifest(dir); manifest.PageID != "page_1" || len(manifest.Photos) != 0 {
+		t.Errorf("Expected the manifest to be saved without the failed photo, got %+v", manifest)
+	}
+
+	library.mu.Lock()
+	library.failListing = false
+	library.failImages = map[string]bool{}
+	library.mu.Unlock()
+	library.takeDownloads()
+
+	result, err = client.BackupPhotos(context.Background(), "page_1", opts)
+	if err != nil {
+		t.Fatalf("Expected the resumed backup to succeed, got %v", err)
+	}
+	if result.Downloaded != 3 || result.Failed != 0 {
+		t.Errorf("Expected every photo to be downloaded on resume, got %+v", result)
+	}
+
+	// A cancelled run leaves the manifest intact
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.BackupPhotos(ctx, "page_1", opts); err == nil {
+		t.Error("Expected a cancelled backup to fail")
+	}
+	if manifest, _ := LoadBackupManifest(dir); len(manifest.Photos) != 3 {
+		t.Errorf("Expected the manifest to keep every photo, got %d", len(manifest.Photos))
+	}
+}
@@ -1,6 +1,7 @@
 package facebook
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -12,6 +13,7 @@ import (
 const (
 	DefaultAPIVersion = "v23.0"
 	BaseURL          = "https://graph.facebook.com"
+	VideoBaseURL     = "https://graph-video.facebook.com"
 )
 
 // Client represents a Facebook Pages API client
@@ -20,6 +22,16 @@ type Client struct {
 	APIVersion  string
 	HTTPClient  *http.Client
 	BaseURL     string
+	// VideoBaseURL is the host used for video uploads
+	VideoBaseURL string
+	// Metrics records Graph call latency, errors, retries and rate-limit
+	// usage when set
+	Metrics *MetricsRegistry
+	// Tracer starts a client span for every Graph call when set
+	Tracer *Tracer
+
+	// ctx is used by methods that take no context, see WithContext
+	ctx context.Context
 }
 
 // NewClient creates a new Facebook Pages API client
@@ -30,7 +42,8 @@ func NewClient(accessToken string) *Client {
 		HTTPClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		BaseURL: BaseURL,
+		BaseURL:      BaseURL,
+		VideoBaseURL: VideoBaseURL,
 	}
 }
 
@@ -39,6 +52,31 @@ func (c *Client) SetAPIVersion(version string) {
 	c.APIVersion = version
 }
 
+// WithAccessToken returns a copy of the client that uses a different access token,
+// e.g. a page token obtained from GetPages
+func (c *Client) WithAccessToken(accessToken string) *Client {
+	clone := *c
+	clone.AccessToken = accessToken
+	return &clone
+}
+
+// WithContext returns a copy of the client whose methods without a context
+// parameter run with ctx, so they are cancelled with it and their Graph
+// calls join its trace
+func (c *Client) WithContext(ctx context.Context) *Client {
+	clone := *c
+	clone.ctx = ctx
+	return &clone
+}
+
+// requestContext returns the context set by WithContext or context.Background
+func (c *Client) requestContext() context.Context {
+	if c.ctx != nil {
+		return c.ctx
+	}
+	return context.Background()
+}
+
 // buildURL constructs the full API URL
 func (c *Client) buildURL(endpoint string) string {
 	return fmt.Sprintf("%s/%s/%s", c.BaseURL, c.APIVersion, endpoint)
@@ -46,9 +84,15 @@ func (c *Client) buildURL(endpoint string) string {
 
 // makeRequest performs an HTTP request to the Facebook API
 func (c *Client) makeRequest(method, endpoint string, params url.Values, body io.Reader) (*http.Response, error) {
+	return c.makeRequestContext(c.requestContext(), method, endpoint, params, body)
+}
+
+// makeRequestContext performs an HTTP request to the Facebook API that is
+// cancelled with ctx
+func (c *Client) makeRequestContext(ctx context.Context, method, endpoint string, params url.Values, body io.Reader) (*http.Response, error) {
 	apiURL := c.buildURL(endpoint)
 	
-	req, err := http.NewRequest(method, apiURL, body)
+	req, err := http.NewRequestWithContext(ctx, method, apiURL, body)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
@@ -69,7 +113,7 @@ func (c *Client) makeRequest(method, endpoint string, params url.Values, body io
 		req.Header.Set("Content-Type", "application/json")
 	}
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("making request: %w", err)
 	}
@@ -77,6 +121,71 @@ func (c *Client) makeRequest(method, endpoint string, params url.Values, body io
 	return resp, nil
 }
 
+// do sends a Graph API request in a client span, recording its latency,
+// status and the rate-limit usage Facebook reports
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	return c.doWith(c.HTTPClient, req)
+}
+
+// doTransfer is do for large uploads and downloads. HTTPClient.Timeout
+// covers the whole body, which a slow link cannot move within 30 seconds,
+// so these requests are only bounded by their context.
+func (c *Client) doTransfer(req *http.Request) (*http.Response, error) {
+	return c.doWith(c.transferHTTPClient(), req)
+}
+
+// transferHTTPClient returns a copy of HTTPClient without an overall timeout
+func (c *Client) transferHTTPClient() *http.Client {
+	client := *c.HTTPClient
+	client.Timeout = 0
+	return &client
+}
+
+// doWith sends a Graph API request through httpClient, see do
+func (c *Client) doWith(httpClient *http.Client, req *http.Request) (*http.Response, error) {
+	endpoint := graphEndpointLabel(req.URL.Path)
+	ctx, span := c.Tracer.Start(req.Context(), req.Method+" "+endpoint, SpanKindClient)
+	defer span.End()
+	span.SetAttribute("http.request.method", req.Method)
+	span.SetAttribute("server.address", req.URL.Host)
+	span.SetAttribute("graph.endpoint", endpoint)
+	span.SetAttribute("graph.api_version", c.APIVersion)
+
+	start := time.Now()
+	resp, err := httpClient.Do(req.WithContext(ctx))
+	c.observeGraphRequest(req, resp, time.Since(start))
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	span.SetAttribute("http.response.status_code", resp.StatusCode)
+	if traceID := resp.Header.Get("X-Fb-Trace-Id"); traceID != "" {
+		span.SetAttribute("graph.fbtrace_id", traceID)
+	}
+	if resp.StatusCode >= 400 {
+		span.SetStatus(SpanStatusError, resp.Status)
+	}
+	return resp, nil
+}
+
+// APIError is an error response from the Graph API
+type APIError struct {
+	ErrorDetail
+	StatusCode int
+	// Body holds the raw response when it is not a Graph error object
+	Body string
+	raw  bool
+}
+
+func (e *APIError) Error() string {
+	if e.raw {
+		return fmt.Sprintf("API error (status %d): %s", e.StatusCode, e.Body)
+	}
+	return fmt.Sprintf("API error: %s (code: %d)", e.Message, e.Code)
+}
+
 // handleResponse processes the API response and handles errors
 func (c *Client) handleResponse(resp *http.Response, result interface{}) error {
 	defer resp.Body.Close()
@@ -87,11 +196,15 @@ func (c *Client) handleResponse(resp *http.Response, result interface{}) error {
 	}
 
 	if resp.StatusCode >= 400 {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
 		var errorResp ErrorResponse
 		if err := json.Unmarshal(body, &errorResp); err != nil {
-			return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+			apiErr.Body, apiErr.raw = string(body), true
+		} else {
+			apiErr.ErrorDetail = errorResp.Error
 		}
-		return fmt.Errorf("API error: %s (code: %d)", errorResp.Error.Message, errorResp.Error.Code)
+		c.observeGraphError(resp.Request, apiErr)
+		return apiErr
 	}
 
 	if result != nil {
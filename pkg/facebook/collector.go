@@ -0,0 +1,161 @@
+package facebook
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// CollectorConfig configures an insights Collector
+type CollectorConfig struct {
+	// PageIDs are the pages whose insights are collected
+	PageIDs []string
+	// PageMetrics default to the metrics of GetPageInsights
+	PageMetrics []string
+	// Period of page metrics (default day)
+	Period string
+	// PostMetrics default to the metrics of GetPostInsights
+	PostMetrics []string
+	// RecentPosts is how many of each page's latest posts are collected;
+	// zero disables post insights
+	RecentPosts int
+	// Lookback is how far back each run requests page insights (default 7
+	// days), so values Facebook revises after the fact are picked up
+	Lookback time.Duration
+	// Interval between runs of Run (default 1 hour)
+	Interval time.Duration
+	// Logf reports progress; defaults to log.Printf
+	Logf func(format string, args ...interface{})
+}
+
+// CollectResult summarizes a collection run
+type CollectResult struct {
+	Points int      `json:"points"`
+	Stored int      `json:"stored"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Collector periodically copies page and post insights into an InsightStore
+type Collector struct {
+	client *Client
+	store  *InsightStore
+	config CollectorConfig
+	now    func() time.Time
+}
+
+// NewCollector creates a collector writing insights fetched by client to store
+func NewCollector(client *Client, store *InsightStore, config CollectorConfig) *Collector {
+	if config.Period == "" {
+		config.Period = PeriodDay
+	}
+	if config.Lookback <= 0 {
+		config.Lookback = 7 * 24 * time.Hour
+	}
+	if config.Interval <= 0 {
+		config.Interval = time.Hour
+	}
+	if config.Logf == nil {
+		config.Logf = log.Printf
+	}
+
+	return &Collector{
+		client: client,
+		store:  store,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Run collects immediately and then every Interval until ctx is done
+func (c *Collector) Run(ctx context.Context) error {
+	ticker := time.NewTicker(c.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := c.CollectOnce(ctx)
+		if err != nil {
+			return err
+		}
+		c.config.Logf("collector: %d points fetched, %d new or changed, %d errors",
+			result.Points, result.Stored, len(result.Errors))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// CollectOnce fetches insights of every configured page and its recent
+// posts and stores them. Fetch failures are reported in the result so one
+// failing page does not stop the others; the error reports store failures.
+func (c *Collector) CollectOnce(ctx context.Context) (*CollectResult, error) {
+	result := &CollectResult{}
+	now := c.now().UTC()
+	client := c.client.WithContext(ctx)
+
+	for _, pageID := range c.config.PageIDs {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		since := now.Add(-c.config.Lookback)
+		insights, err := client.GetPageInsights(pageID, c.config.PageMetrics, c.config.Period, &since, &now)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("page %s: %v", pageID, err))
+			c.config.Logf("collector: page %s insights failed: %v", pageID, err)
+		} else if err := c.storeInsights(result, MetricObjectPage, pageID, insights, now); err != nil {
+			return result, err
+		}
+
+		if c.config.RecentPosts <= 0 {
+			continue
+		}
+
+		posts, err := client.GetPosts(pageID, c.config.RecentPosts, "id")
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("posts of page %s: %v", pageID, err))
+			c.config.Logf("collector: listing posts of page %s failed: %v", pageID, err)
+			continue
+		}
+
+		for _, post := range posts.Data {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+
+			insights, err := client.GetPostInsights(post.ID, c.config.PostMetrics)
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("post %s: %v", post.ID, err))
+				c.config.Logf("collector: post %s insights failed: %v", post.ID, err)
+				continue
+			}
+			if err := c.storeInsights(result, MetricObjectPost, post.ID, insights, now); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// storeInsights stores the rows of an insights response. Lifetime values come
+// without an end_time; they are stamped with the collection day so the
+// store keeps one snapshot per day.
+func (c *Collector) storeInsights(result *CollectResult, object MetricObject, objectID string, insights *InsightsResponse, collectedAt time.Time) error {
+	rows := insights.Rows(objectID)
+	points := make([]StoredPoint, len(rows))
+	for i, row := range rows {
+		if row.EndTime.IsZero() {
+			row.EndTime = collectedAt.Truncate(24 * time.Hour)
+		}
+		points[i] = StoredPoint{Object: object, InsightRow: row, CollectedAt: collectedAt}
+	}
+
+	stored, err := c.store.Put(points)
+	result.Points += len(points)
+	result.Stored += stored
+	return err
+}
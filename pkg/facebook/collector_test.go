@@ -0,0 +1,122 @@
+package facebook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCollectorStoresInsightsAndReportsPageErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		path := strings.TrimPrefix(req.URL.Path, "/"+DefaultAPIVersion+"/")
+		switch path {
+		case "page_ok/insights":
+			fmt.Fprint(w, `{"data":[{"name":"page_impressions","period":"day","values":[
+				{"value":10,"end_time":"2024-03-03T08:00:00+0000"},
+				{"value":12,"end_time":"2024-03-04T08:00:00+0000"}]}]}`)
+		case "page_ok/posts":
+			fmt.Fprint(w, `{"data":[{"id":"post_1"}]}`)
+		case "post_1/insights":
+			fmt.Fprint(w, `{"data":[{"name":"post_clicks","period":"lifetime","values":[{"value":5}]}]}`)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"error":{"message":"Service unavailable","type":"OAuthException","code":2}}`)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test_token")
+	client.BaseURL = server.URL
+
+	store, err := OpenInsightStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Opening store: %v", err)
+	}
+
+	collector := NewCollector(client, store, CollectorConfig{
+		PageIDs:     []string{"page_bad", "page_ok"},
+		PageMetrics: []string{"page_impressions"},
+		PostMetrics: []string{"post_clicks"},
+		RecentPosts: 5,
+		Logf:        t.Logf,
+	})
+	now := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+	collector.now = func() time.Time { return now }
+
+	// The failing page is reported without stopping the next one
+	result, err := collector.CollectOnce(context.Background())
+	if err != nil {
+		t.Fatalf("Expected collection to succeed, got %v", err)
+	}
+	if len(result.Errors) != 2 || !strings.HasPrefix(result.Errors[0], "page page_bad: ") || !strings.HasPrefix(result.Errors[1], "posts of page page_bad: ") {
+		t.Errorf("Expected insights and posts errors for page_bad, got %v", result.Errors)
+	}
+	if result.Points != 3 || result.Stored != 3 {
+		t.Errorf("Expected 3 points fetched and stored, got %+v", result)
+	}
+
+	// A later run on the same day finds nothing new
+	now = now.Add(time.Hour)
+	result, err = collector.CollectOnce(context.Background())
+	if err != nil || result.Points != 3 || result.Stored != 0 {
+		t.Errorf("Expected unchanged points to be skipped, got %+v (%v)", result, err)
+	}
+
+	// The next day adds a new lifetime snapshot of the post
+	now = now.Add(24 * time.Hour)
+	result, err = collector.CollectOnce(context.Background())
+	if err != nil || result.Stored != 1 {
+		t.Errorf("Expected one new lifetime snapshot, got %+v (%v)", result, err)
+	}
+
+	points, err := store.Query(StoreQuery{ObjectID: "post_1"})
+	if err != nil {
+		t.Fatalf("Querying store: %v", err)
+	}
+	if len(points) != 2 {
+		t.Fatalf("Expected two daily snapshots of post_1, got %d", len(points))
+	}
+	for i, want := range []time.Time{time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)} {
+		if !points[i].EndTime.Equal(want) || points[i].Object != MetricObjectPost || points[i].Value != 5 {
+			t.Errorf("Expected a lifetime snapshot stamped %v, got %+v", want, points[i])
+		}
+	}
+}
+
+func TestCollectorCancelsGraphCalls(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The request blocks until the collector's context is cancelled
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		cancel()
+		<-req.Context().Done()
+	}))
+	defer server.Close()
+
+	client := NewClient("test_token")
+	client.BaseURL = server.URL
+
+	store, err := OpenInsightStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Opening store: %v", err)
+	}
+
+	collector := NewCollector(client, store, CollectorConfig{
+		PageIDs:     []string{"page_1", "page_2"},
+		PageMetrics: []string{"page_impressions"},
+		Logf:        t.Logf,
+	})
+
+	result, err := collector.CollectOnce(ctx)
+	if err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0], "context canceled") {
+		t.Errorf("Expected the in-flight request to be cancelled, got %v", result.Errors)
+	}
+}
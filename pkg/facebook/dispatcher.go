@@ -0,0 +1,346 @@
+package facebook
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// EventType identifies the kind of page activity carried by an Event
+type EventType string
+
+const (
+	EventPostCreated    EventType = "post.created"
+	EventCommentCreated EventType = "comment.created"
+)
+
+// Event represents page activity forwarded to internal consumers
+type Event struct {
+	ID         string    `json:"id"`
+	Type       EventType `json:"type"`
+	PageID     string    `json:"page_id"`
+	PostID     string    `json:"post_id,omitempty"`
+	CommentID  string    `json:"comment_id,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Post       *Post     `json:"post,omitempty"`
+	Comment    *Comment  `json:"comment,omitempty"`
+}
+
+// Sink receives events from a Dispatcher
+type Sink interface {
+	// Name identifies the sink in dead-letter records, it must be unique per dispatcher
+	Name() string
+	Deliver(ctx context.Context, event Event) error
+}
+
+// RetryPolicy controls how often a failed delivery is retried
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultRetryPolicy is used for sinks registered without an explicit policy
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    5,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     30 * time.Second,
+}
+
+// backoff returns the delay before the given retry attempt (1-based)
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.InitialBackoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	return delay
+}
+
+// DeadLetter records an event that could not be delivered to a sink
+type DeadLetter struct {
+	Sink     string    `json:"sink"`
+	Event    Event     `json:"event"`
+	Error    string    `json:"error"`
+	Attempts int       `json:"attempts"`
+	FailedAt time.Time `json:"failed_at"`
+}
+
+// DeadLetterStore persists failed deliveries as NDJSON
+type DeadLetterStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewDeadLetterStore creates a dead-letter store backed by the given file
+func NewDeadLetterStore(path string) *DeadLetterStore {
+	return &DeadLetterStore{path: path}
+}
+
+// Path returns the dead-letter file path
+func (s *DeadLetterStore) Path() string {
+	return s.path
+}
+
+// Add appends a dead-letter record to the store
+func (s *DeadLetterStore) Add(record DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.append(record)
+}
+
+// Load reads all dead-letter records from the store
+func (s *DeadLetterStore) Load() ([]DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.load()
+}
+
+func (s *DeadLetterStore) append(record DeadLetter) error {
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening dead-letter file: %w", err)
+	}
+	defer file.Close()
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshaling dead-letter record: %w", err)
+	}
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("writing dead-letter record: %w", err)
+	}
+
+	return nil
+}
+
+func (s *DeadLetterStore) load() ([]DeadLetter, error) {
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening dead-letter file: %w", err)
+	}
+	defer file.Close()
+
+	var records []DeadLetter
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record DeadLetter
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("decoding dead-letter record: %w", err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading dead-letter file: %w", err)
+	}
+
+	return records, nil
+}
+
+// rewrite atomically replaces the store contents with the given records
+func (s *DeadLetterStore) rewrite(records []DeadLetter) error {
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("creating dead-letter temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	encoder := json.NewEncoder(tmp)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			tmp.Close()
+			return fmt.Errorf("writing dead-letter record: %w", err)
+		}
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing dead-letter temp file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("replacing dead-letter file: %w", err)
+	}
+
+	return nil
+}
+
+// registeredSink pairs a sink with its retry policy
+type registeredSink struct {
+	sink   Sink
+	policy RetryPolicy
+}
+
+// Dispatcher fans events out to sinks with per-sink retry and dead-lettering
+type Dispatcher struct {
+	// Metrics counts retried deliveries by sink when set
+	Metrics *MetricsRegistry
+
+	sinks      []registeredSink
+	deadLetter *DeadLetterStore
+}
+
+// NewDispatcher creates a dispatcher. deadLetter may be nil, in which case
+// events that exhaust their retries are dropped.
+func NewDispatcher(deadLetter *DeadLetterStore) *Dispatcher {
+	return &Dispatcher{
+		deadLetter: deadLetter,
+	}
+}
+
+// AddSink registers a sink using DefaultRetryPolicy
+func (d *Dispatcher) AddSink(sink Sink) {
+	d.AddSinkWithPolicy(sink, DefaultRetryPolicy)
+}
+
+// AddSinkWithPolicy registers a sink with a custom retry policy
+func (d *Dispatcher) AddSinkWithPolicy(sink Sink, policy RetryPolicy) {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 1
+	}
+	d.sinks = append(d.sinks, registeredSink{sink: sink, policy: policy})
+}
+
+// Dispatch delivers an event to every sink concurrently. Deliveries that
+// exhaust their retries are written to the dead-letter store; the returned
+// error joins the failures of all sinks.
+func (d *Dispatcher) Dispatch(ctx context.Context, event Event) error {
+	errs := make([]error, len(d.sinks))
+
+	var wg sync.WaitGroup
+	for i, registered := range d.sinks {
+		wg.Add(1)
+		go func(i int, registered registeredSink) {
+			defer wg.Done()
+			errs[i] = d.deliver(ctx, registered, event)
+		}(i, registered)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// deliver sends an event to a single sink, retrying and dead-lettering on failure
+func (d *Dispatcher) deliver(ctx context.Context, registered registeredSink, event Event) error {
+	attempts, err := d.deliverWithRetry(ctx, registered, event)
+	if err == nil {
+		return nil
+	}
+
+	err = fmt.Errorf("delivering %s to sink %s: %w", event.ID, registered.sink.Name(), err)
+	if d.deadLetter != nil {
+		record := DeadLetter{
+			Sink:     registered.sink.Name(),
+			Event:    event,
+			Error:    err.Error(),
+			Attempts: attempts,
+			FailedAt: time.Now().UTC(),
+		}
+		if dlErr := d.deadLetter.Add(record); dlErr != nil {
+			return errors.Join(err, dlErr)
+		}
+	}
+
+	return err
+}
+
+// deliverWithRetry attempts delivery according to the sink's retry policy
+func (d *Dispatcher) deliverWithRetry(ctx context.Context, registered registeredSink, event Event) (int, error) {
+	var err error
+	for attempt := 1; attempt <= registered.policy.MaxAttempts; attempt++ {
+		if err = registered.sink.Deliver(ctx, event); err == nil {
+			return attempt, nil
+		}
+
+		if attempt == registered.policy.MaxAttempts {
+			return attempt, err
+		}
+
+		d.Metrics.recordDeliveryRetry(registered.sink.Name())
+		select {
+		case <-ctx.Done():
+			return attempt, errors.Join(err, ctx.Err())
+		case <-time.After(registered.policy.backoff(attempt)):
+		}
+	}
+	return registered.policy.MaxAttempts, err
+}
+
+// ReplayResult summarizes a dead-letter replay
+type ReplayResult struct {
+	Delivered int `json:"delivered"`
+	Failed    int `json:"failed"`
+	Skipped   int `json:"skipped"`
+}
+
+// Replay redelivers every dead-lettered event to the sink it failed on.
+// Successfully delivered records are removed from the dead-letter file,
+// records that fail again or whose sink is no longer registered are kept.
+func (d *Dispatcher) Replay(ctx context.Context) (*ReplayResult, error) {
+	if d.deadLetter == nil {
+		return nil, fmt.Errorf("no dead-letter store configured")
+	}
+
+	d.deadLetter.mu.Lock()
+	defer d.deadLetter.mu.Unlock()
+
+	records, err := d.deadLetter.load()
+	if err != nil {
+		return nil, err
+	}
+
+	sinks := make(map[string]registeredSink, len(d.sinks))
+	for _, registered := range d.sinks {
+		sinks[registered.sink.Name()] = registered
+	}
+
+	result := &ReplayResult{}
+	var remaining []DeadLetter
+	for i, record := range records {
+		if ctx.Err() != nil {
+			remaining = append(remaining, records[i:]...)
+			break
+		}
+
+		registered, ok := sinks[record.Sink]
+		if !ok {
+			result.Skipped++
+			remaining = append(remaining, record)
+			continue
+		}
+
+		attempts, err := d.deliverWithRetry(ctx, registered, record.Event)
+		if err != nil {
+			result.Failed++
+			record.Error = err.Error()
+			record.Attempts += attempts
+			record.FailedAt = time.Now().UTC()
+			remaining = append(remaining, record)
+			continue
+		}
+		result.Delivered++
+	}
+
+	if err := d.deadLetter.rewrite(remaining); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
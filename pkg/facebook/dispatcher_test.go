@@ -0,0 +1,93 @@
+package facebook
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHTTPSinkSignsCallbacks(t *testing.T) {
+	var verifyErr error
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		verifyErr = VerifyEventSignature("secret", req.Header.Get(EventSignatureHeader), body, time.Minute)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sink := NewHTTPSink(server.URL, "secret")
+	event := Event{ID: "post.created:1_2", Type: EventPostCreated, PageID: "1", PostID: "1_2"}
+
+	if err := sink.Deliver(context.Background(), event); err != nil {
+		t.Fatalf("Expected delivery to succeed, got %v", err)
+	}
+	if verifyErr != nil {
+		t.Errorf("Expected signature to verify, got %v", verifyErr)
+	}
+}
+
+func TestDispatcherDeadLetterAndReplay(t *testing.T) {
+	var healthy atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	store := NewDeadLetterStore(filepath.Join(t.TempDir(), "deadletter.ndjson"))
+	registry := NewMetricsRegistry()
+	dispatcher := NewDispatcher(store)
+	dispatcher.Metrics = registry
+	httpSink := NewHTTPSink(server.URL, "secret")
+	dispatcher.AddSinkWithPolicy(httpSink, RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond})
+
+	channel := NewChannelSink("test", 1)
+	dispatcher.AddSink(channel)
+
+	event := Event{ID: "comment.created:9", Type: EventCommentCreated, PageID: "1", CommentID: "9"}
+	if err := dispatcher.Dispatch(context.Background(), event); err == nil {
+		t.Fatal("Expected dispatch to report the failing sink")
+	}
+
+	if got := <-channel.Events(); got.ID != event.ID {
+		t.Errorf("Expected channel sink to receive %s, got %s", event.ID, got.ID)
+	}
+
+	records, err := store.Load()
+	if err != nil {
+		t.Fatalf("Loading dead letters: %v", err)
+	}
+	if len(records) != 1 || records[0].Attempts != 2 || records[0].Event.ID != event.ID {
+		t.Fatalf("Unexpected dead-letter records: %+v", records)
+	}
+
+	var out strings.Builder
+	registry.WriteText(&out)
+	if retries := fmt.Sprintf(`event_delivery_retries_total{sink=%q} 1`, httpSink.Name()); !strings.Contains(out.String(), retries) {
+		t.Errorf("Expected %s in:\n%s", retries, out.String())
+	}
+
+	healthy.Store(true)
+	result, err := dispatcher.Replay(context.Background())
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if result.Delivered != 1 || result.Failed != 0 {
+		t.Errorf("Unexpected replay result: %+v", result)
+	}
+
+	records, _ = store.Load()
+	if len(records) != 0 {
+		t.Errorf("Expected dead-letter file to be empty after replay, got %d records", len(records))
+	}
+}
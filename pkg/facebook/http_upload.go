@@ -0,0 +1,231 @@
+package facebook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// MaxVideoUploadBytes bounds the videos accepted by the reels and stories endpoints
+const MaxVideoUploadBytes = 1 << 30
+
+// maxUploadFieldBytes bounds the text fields of an upload request
+const maxUploadFieldBytes = 64 << 10
+
+// receivedUpload is a media file received in a multipart request and spooled
+// to a temporary file so it can be uploaded with a known size
+type receivedUpload struct {
+	fields      map[string]string
+	file        *os.File
+	size        int64
+	contentType string
+}
+
+// Close removes the temporary file
+func (u *receivedUpload) Close() error {
+	u.file.Close()
+	return os.Remove(u.file.Name())
+}
+
+// receiveUpload reads a multipart/form-data request with a "file" part and
+// any number of text fields. Files larger than maxBytes are rejected with a
+// *MediaValidationError.
+func receiveUpload(w http.ResponseWriter, req *http.Request, maxBytes int64) (*receivedUpload, error) {
+	req.Body = http.MaxBytesReader(w, req.Body, maxBytes+1<<20)
+
+	reader, err := req.MultipartReader()
+	if err != nil {
+		return nil, fmt.Errorf("expected multipart/form-data: %w", err)
+	}
+
+	upload := &receivedUpload{fields: make(map[string]string)}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			upload.discard()
+			return nil, fmt.Errorf("reading multipart body: %w", err)
+		}
+
+		if part.FormName() != "file" {
+			value, err := io.ReadAll(io.LimitReader(part, maxUploadFieldBytes))
+			part.Close()
+			if err != nil {
+				upload.discard()
+				return nil, fmt.Errorf("reading %s field: %w", part.FormName(), err)
+			}
+			upload.fields[part.FormName()] = string(value)
+			continue
+		}
+
+		if upload.file != nil {
+			part.Close()
+			upload.discard()
+			return nil, fmt.Errorf("only one file may be uploaded")
+		}
+
+		err = upload.spool(part, maxBytes)
+		part.Close()
+		if err != nil {
+			upload.discard()
+			return nil, err
+		}
+	}
+
+	if upload.file == nil {
+		return nil, fmt.Errorf("file is required")
+	}
+
+	return upload, nil
+}
+
+// spool copies the file part to a temporary file and detects its content type
+func (u *receivedUpload) spool(part *multipart.Part, maxBytes int64) error {
+	file, err := os.CreateTemp("", "fb-upload-*")
+	if err != nil {
+		return fmt.Errorf("creating temporary file: %w", err)
+	}
+	u.file = file
+
+	u.size, err = io.Copy(file, io.LimitReader(part, maxBytes+1))
+	if err != nil {
+		return fmt.Errorf("receiving file: %w", err)
+	}
+	if u.size > maxBytes {
+		return &MediaValidationError{
+			Err:    ErrMediaTooLarge,
+			Detail: fmt.Sprintf("media exceeds the %d byte limit", maxBytes),
+		}
+	}
+	if u.size == 0 {
+		return fmt.Errorf("file is empty")
+	}
+
+	header := make([]byte, 512)
+	n, err := file.ReadAt(header, 0)
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("reading file: %w", err)
+	}
+	u.contentType = http.DetectContentType(header[:n])
+	// Some containers (e.g. QuickTime) are not sniffed; trust the client then
+	if declared := part.Header.Get("Content-Type"); u.contentType == "application/octet-stream" && declared != "" {
+		u.contentType = declared
+	}
+
+	return nil
+}
+
+// discard removes a partially received upload
+func (u *receivedUpload) discard() {
+	if u.file != nil {
+		u.Close()
+	}
+}
+
+// isVideo reports whether the upload is a video
+func (u *receivedUpload) isVideo() bool {
+	return strings.HasPrefix(u.contentType, "video/")
+}
+
+// isImage reports whether the upload is an image
+func (u *receivedUpload) isImage() bool {
+	return strings.HasPrefix(u.contentType, "image/")
+}
+
+// uploadErrorStatus maps errors from receiving or validating media to an HTTP status
+func uploadErrorStatus(err error) int {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.Is(err, ErrMediaTooLarge), errors.As(err, &maxBytesErr):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, ErrUnsupportedMediaType):
+		return http.StatusUnsupportedMediaType
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// photoUpload is a photo upload received by the photo routes: either a
+// multipart file streamed from the request or a JSON body with a URL
+type photoUpload struct {
+	Message   string `json:"message"`
+	Published *bool  `json:"published"`
+	URL       string `json:"url"`
+
+	file io.Reader
+}
+
+// published reports whether the photo should be published, defaulting to true
+func (u *photoUpload) published() bool {
+	return u.Published == nil || *u.Published
+}
+
+// readPhotoUpload parses a photo upload request. Multipart requests are not
+// buffered: text fields must precede the "file" part, which is returned
+// unread so it can be streamed to Facebook.
+func readPhotoUpload(w http.ResponseWriter, req *http.Request, maxBytes int64) (*photoUpload, error) {
+	if req.ContentLength > maxBytes+1<<20 {
+		return nil, &MediaValidationError{
+			Err:    ErrMediaTooLarge,
+			Detail: fmt.Sprintf("request exceeds the %d byte limit", maxBytes),
+		}
+	}
+	req.Body = http.MaxBytesReader(w, req.Body, maxBytes+1<<20)
+
+	upload := &photoUpload{}
+
+	if !strings.HasPrefix(req.Header.Get("Content-Type"), "multipart/") {
+		if err := json.NewDecoder(req.Body).Decode(upload); err != nil {
+			return nil, fmt.Errorf("invalid photo body: %w", err)
+		}
+		if upload.URL == "" {
+			return nil, fmt.Errorf("url is required")
+		}
+		return upload, nil
+	}
+
+	reader, err := req.MultipartReader()
+	if err != nil {
+		return nil, fmt.Errorf("expected multipart/form-data: %w", err)
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, fmt.Errorf("file is required")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading multipart body: %w", err)
+		}
+
+		if part.FormName() == "file" {
+			upload.file = part
+			return upload, nil
+		}
+
+		value, err := io.ReadAll(io.LimitReader(part, maxUploadFieldBytes))
+		part.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading %s field: %w", part.FormName(), err)
+		}
+
+		switch part.FormName() {
+		case "message":
+			upload.Message = string(value)
+		case "published":
+			published, err := strconv.ParseBool(string(value))
+			if err != nil {
+				return nil, fmt.Errorf("invalid published field: %w", err)
+			}
+			upload.Published = &published
+		}
+	}
+}
@@ -0,0 +1,360 @@
+package facebook
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// InsightStoreFileName is the data file of an insight store directory
+const InsightStoreFileName = "insights.ndjson"
+
+// StoredPoint is an insight observation kept in an InsightStore
+type StoredPoint struct {
+	Object MetricObject `json:"object"`
+	InsightRow
+	CollectedAt time.Time `json:"collected_at"`
+}
+
+// storeKey identifies a point; later observations of a key replace earlier ones
+type storeKey struct {
+	object    MetricObject
+	objectID  string
+	metric    string
+	period    string
+	endTime   int64
+	dimension string
+}
+
+func (p *StoredPoint) key() storeKey {
+	return storeKey{
+		object:    p.Object,
+		objectID:  p.ObjectID,
+		metric:    p.Metric,
+		period:    p.Period,
+		endTime:   p.EndTime.Unix(),
+		dimension: p.Dimension,
+	}
+}
+
+// StoreQuery selects points from an InsightStore. Empty fields match
+// everything; Since and Until bound end_time inclusively.
+type StoreQuery struct {
+	ObjectID string
+	Metrics  []string
+	Period   string
+	Since    time.Time
+	Until    time.Time
+}
+
+// storeQuery converts a parsed insights request into a StoreQuery for objectID
+func (q *InsightsQuery) storeQuery(objectID string) StoreQuery {
+	query := StoreQuery{ObjectID: objectID, Metrics: q.Metrics, Period: q.Period}
+	if q.Since != nil {
+		query.Since = *q.Since
+	}
+	if q.Until != nil {
+		query.Until = *q.Until
+	}
+	return query
+}
+
+// InsightStore is an embedded time-series store for insights. Points are
+// appended to an NDJSON file and indexed in memory, deduplicated by object,
+// metric, period, end_time and dimension. One process may write to a store
+// while others read it; readers pick up appended points on every query.
+type InsightStore struct {
+	mu       sync.Mutex
+	path     string
+	offset   int64
+	points   []StoredPoint
+	index    map[storeKey]int
+	byObject map[string][]int
+}
+
+// OpenInsightStore opens or creates the store in dir
+func OpenInsightStore(dir string) (*InsightStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating store directory: %w", err)
+	}
+
+	s := &InsightStore{path: filepath.Join(dir, InsightStoreFileName)}
+	s.reset()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.sync(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// reset clears the in-memory index
+func (s *InsightStore) reset() {
+	s.offset = 0
+	s.points = nil
+	s.index = make(map[storeKey]int)
+	s.byObject = make(map[string][]int)
+}
+
+// sync indexes points appended to the file since the last read. A trailing
+// line without a newline is a write in progress and is left for later.
+func (s *InsightStore) sync() error {
+	file, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("opening insight store: %w", err)
+	}
+	defer file.Close()
+
+	// A file shorter than what was read has been compacted; reload it
+	if info, err := file.Stat(); err == nil && info.Size() < s.offset {
+		s.reset()
+	}
+
+	if _, err := file.Seek(s.offset, io.SeekStart); err != nil {
+		return fmt.Errorf("seeking insight store: %w", err)
+	}
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading insight store: %w", err)
+		}
+
+		lineStart := s.offset
+		s.offset += int64(len(line))
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var point StoredPoint
+		if err := json.Unmarshal(line, &point); err != nil {
+			return fmt.Errorf("parsing insight store at byte %d: %w", lineStart, err)
+		}
+		s.addPoint(point)
+	}
+}
+
+// addPoint adds a point to the in-memory index, replacing an earlier
+// observation of the same key
+func (s *InsightStore) addPoint(point StoredPoint) {
+	key := point.key()
+	if i, ok := s.index[key]; ok {
+		s.points[i] = point
+		return
+	}
+
+	s.index[key] = len(s.points)
+	s.byObject[point.ObjectID] = append(s.byObject[point.ObjectID], len(s.points))
+	s.points = append(s.points, point)
+}
+
+// Put stores points, skipping those already stored with the same value,
+// and returns how many were written
+func (s *InsightStore) Put(points []StoredPoint) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.sync(); err != nil {
+		return 0, err
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	var changed []StoredPoint
+	for _, point := range points {
+		point.EndTime = point.EndTime.UTC()
+		if i, ok := s.index[point.key()]; ok && s.points[i].Value == point.Value {
+			continue
+		}
+		if err := encoder.Encode(point); err != nil {
+			return 0, fmt.Errorf("encoding insight point: %w", err)
+		}
+		changed = append(changed, point)
+	}
+	if len(changed) == 0 {
+		return 0, nil
+	}
+
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return 0, fmt.Errorf("opening insight store: %w", err)
+	}
+	defer file.Close()
+
+	// Drop a partial line left by an interrupted write
+	if err := file.Truncate(s.offset); err != nil {
+		return 0, fmt.Errorf("truncating insight store: %w", err)
+	}
+	if _, err := file.WriteAt(buf.Bytes(), s.offset); err != nil {
+		return 0, fmt.Errorf("writing insight store: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		return 0, fmt.Errorf("syncing insight store: %w", err)
+	}
+
+	s.offset += int64(buf.Len())
+	for _, point := range changed {
+		s.addPoint(point)
+	}
+	return len(changed), nil
+}
+
+// Query returns the matching points ordered by object, metric, period,
+// end_time and dimension
+func (s *InsightStore) Query(query StoreQuery) ([]StoredPoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.sync(); err != nil {
+		return nil, err
+	}
+
+	metrics := make(map[string]bool, len(query.Metrics))
+	for _, metric := range query.Metrics {
+		metrics[metric] = true
+	}
+
+	match := func(point *StoredPoint) bool {
+		switch {
+		case len(metrics) > 0 && !metrics[point.Metric]:
+			return false
+		case query.Period != "" && point.Period != query.Period:
+			return false
+		case !query.Since.IsZero() && point.EndTime.Before(query.Since):
+			return false
+		case !query.Until.IsZero() && point.EndTime.After(query.Until):
+			return false
+		}
+		return true
+	}
+
+	var result []StoredPoint
+	if query.ObjectID != "" {
+		for _, i := range s.byObject[query.ObjectID] {
+			if match(&s.points[i]) {
+				result = append(result, s.points[i])
+			}
+		}
+	} else {
+		for i := range s.points {
+			if match(&s.points[i]) {
+				result = append(result, s.points[i])
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		switch {
+		case a.ObjectID != b.ObjectID:
+			return a.ObjectID < b.ObjectID
+		case a.Metric != b.Metric:
+			return a.Metric < b.Metric
+		case a.Period != b.Period:
+			return a.Period < b.Period
+		case !a.EndTime.Equal(b.EndTime):
+			return a.EndTime.Before(b.EndTime)
+		}
+		return a.Dimension < b.Dimension
+	})
+	return result, nil
+}
+
+// Compact rewrites the data file with only the latest observation of each
+// point. It must not run while another process writes to the store.
+func (s *InsightStore) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.sync(); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), InsightStoreFileName+".*")
+	if err != nil {
+		return fmt.Errorf("creating store temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	writer := bufio.NewWriter(tmp)
+	encoder := json.NewEncoder(writer)
+	for i := range s.points {
+		if err := encoder.Encode(&s.points[i]); err != nil {
+			tmp.Close()
+			return fmt.Errorf("encoding insight point: %w", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing compacted store: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing store temp file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("replacing insight store: %w", err)
+	}
+
+	s.reset()
+	return s.sync()
+}
+
+// PointsToInsights groups stored points back into an InsightsResponse, so
+// stored history can be served and exported like live insights
+func PointsToInsights(points []StoredPoint) *InsightsResponse {
+	type seriesKey struct {
+		metric string
+		period string
+	}
+	type valueKey struct {
+		series  seriesKey
+		endTime int64
+	}
+
+	resp := &InsightsResponse{Data: []Insight{}}
+	seriesIndex := make(map[seriesKey]int)
+	valueIndex := make(map[valueKey]int)
+
+	for _, point := range points {
+		sk := seriesKey{metric: point.Metric, period: point.Period}
+		si, ok := seriesIndex[sk]
+		if !ok {
+			si = len(resp.Data)
+			seriesIndex[sk] = si
+			resp.Data = append(resp.Data, Insight{Name: point.Metric, Period: point.Period})
+		}
+		insight := &resp.Data[si]
+
+		if point.Dimension == "" {
+			insight.Values = append(insight.Values, Value{Value: point.Value, EndTime: FacebookTime{point.EndTime}})
+			continue
+		}
+
+		vk := valueKey{series: sk, endTime: point.EndTime.Unix()}
+		vi, ok := valueIndex[vk]
+		if !ok {
+			vi = len(insight.Values)
+			valueIndex[vk] = vi
+			insight.Values = append(insight.Values, Value{Value: map[string]interface{}{}, EndTime: FacebookTime{point.EndTime}})
+		}
+		insight.Values[vi].Value.(map[string]interface{})[point.Dimension] = point.Value
+	}
+
+	return resp
+}
@@ -0,0 +1,60 @@
+package facebook
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInsightStoreDeduplicatesAndReloads(t *testing.T) {
+	dir := t.TempDir()
+	store, err := OpenInsightStore(dir)
+	if err != nil {
+		t.Fatalf("Opening store: %v", err)
+	}
+
+	day := time.Date(2024, 1, 2, 8, 0, 0, 0, time.UTC)
+	point := func(endTime time.Time, dimension string, value float64) StoredPoint {
+		return StoredPoint{Object: MetricObjectPage, InsightRow: InsightRow{
+			ObjectID: "page", Metric: "page_impressions", Period: PeriodDay,
+			EndTime: endTime, Dimension: dimension, Value: value,
+		}}
+	}
+
+	stored, err := store.Put([]StoredPoint{point(day, "", 10), point(day.Add(24*time.Hour), "", 12)})
+	if err != nil || stored != 2 {
+		t.Fatalf("Expected 2 points stored, got %d (%v)", stored, err)
+	}
+
+	// Unchanged values are skipped; revised values replace the old ones
+	stored, err = store.Put([]StoredPoint{point(day, "", 10), point(day.Add(24*time.Hour), "", 13)})
+	if err != nil || stored != 1 {
+		t.Fatalf("Expected 1 revised point stored, got %d (%v)", stored, err)
+	}
+
+	reopened, err := OpenInsightStore(dir)
+	if err != nil {
+		t.Fatalf("Reopening store: %v", err)
+	}
+	points, err := reopened.Query(StoreQuery{ObjectID: "page", Since: day.Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("Querying store: %v", err)
+	}
+	if len(points) != 1 || points[0].Value != 13 {
+		t.Fatalf("Expected the revised point only, got %+v", points)
+	}
+
+	if err := reopened.Compact(); err != nil {
+		t.Fatalf("Compacting store: %v", err)
+	}
+
+	// The first store notices the compacted file and reloads it
+	points, err = store.Query(StoreQuery{ObjectID: "page"})
+	if err != nil || len(points) != 2 {
+		t.Fatalf("Expected 2 points after compaction, got %d (%v)", len(points), err)
+	}
+
+	insights := PointsToInsights(points)
+	if len(insights.Data) != 1 || len(insights.Data[0].Values) != 2 || insights.Data[0].Values[1].Float() != 13 {
+		t.Errorf("Unexpected insights %+v", insights.Data)
+	}
+}
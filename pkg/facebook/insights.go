@@ -1,43 +1,76 @@
 package facebook
 
 import (
+	"context"
 	"fmt"
 	"net/url"
+	"strings"
 	"time"
 )
 
-// GetPageInsights retrieves insights data for a Facebook page
+// defaultPageMetrics are requested when GetPageInsights is called without
+// metrics; every one supports the day, week and days_28 periods
+var defaultPageMetrics = []string{
+	"page_impressions",
+	"page_impressions_unique",
+	"page_posts_impressions",
+	"page_post_engagements",
+	"page_views_total",
+	"page_video_views",
+}
+
+// defaultPostMetrics are requested when GetPostInsights is called without metrics
+var defaultPostMetrics = []string{
+	"post_impressions",
+	"post_impressions_unique",
+	"post_clicks",
+	"post_reactions_by_type_total",
+	"post_video_views",
+}
+
+// GetPageInsights retrieves insights data for a Facebook page.
+// Ranges longer than MaxInsightsRange are split into chunks and merged.
+// Metrics the catalog knows to be deprecated in the client's API version or
+// unavailable for the period fail with a *MetricError before any request.
 func (c *Client) GetPageInsights(pageID string, metrics []string, period string, since, until *time.Time) (*InsightsResponse, error) {
-	params := url.Values{}
-	
-	// Set metrics
-	if len(metrics) == 0 {
-		metrics = []string{
-			"page_fans",
-			"page_fan_adds",
-			"page_fan_removes",
-			"page_views_total",
-			"page_impressions",
-			"page_posts_impressions",
-			"page_engaged_users",
+	// Without until Graph reads up to now, so a distant since still needs chunking
+	if since != nil {
+		end := time.Now()
+		if until != nil {
+			end = *until
+		}
+		if end.Sub(*since) > MaxInsightsRange {
+			return c.GetPageInsightsRange(c.requestContext(), pageID, metrics, period, *since, end, nil)
 		}
 	}
 	
-	metricsStr := ""
-	for i, metric := range metrics {
-		if i > 0 {
-			metricsStr += ","
-		}
-		metricsStr += metric
+	metrics, period = pageInsightsDefaults(metrics, period)
+	if err := ValidateMetrics(MetricObjectPage, metrics, period, c.APIVersion); err != nil {
+		return nil, err
 	}
-	params.Set("metric", metricsStr)
 	
-	// Set period (day, week, days_28)
-	if period != "" {
-		params.Set("period", period)
-	} else {
-		params.Set("period", "day")
+	return c.getPageInsights(c.requestContext(), pageID, metrics, period, since, until)
+}
+
+// pageInsightsDefaults fills in the default metrics and period
+func pageInsightsDefaults(metrics []string, period string) ([]string, string) {
+	if len(metrics) == 0 {
+		metrics = defaultPageMetrics
+	}
+	if period == "" {
+		period = PeriodDay
 	}
+	return metrics, period
+}
+
+// getPageInsights performs a single page insights request
+func (c *Client) getPageInsights(ctx context.Context, pageID string, metrics []string, period string, since, until *time.Time) (*InsightsResponse, error) {
+	params := url.Values{}
+	
+	// Set metrics and period (day, week, days_28)
+	metrics, period = pageInsightsDefaults(metrics, period)
+	params.Set("metric", strings.Join(metrics, ","))
+	params.Set("period", period)
 	
 	// Set date range
 	if since != nil {
@@ -48,7 +81,7 @@ func (c *Client) GetPageInsights(pageID string, metrics []string, period string,
 	}
 
 	endpoint := fmt.Sprintf("%s/insights", pageID)
-	resp, err := c.makeRequest("GET", endpoint, params, nil)
+	resp, err := c.makeRequestContext(ctx, "GET", endpoint, params, nil)
 	if err != nil {
 		return nil, fmt.Errorf("getting page insights: %w", err)
 	}
@@ -61,20 +94,16 @@ func (c *Client) GetPageInsights(pageID string, metrics []string, period string,
 	return &insightsResp, nil
 }
 
-// GetPostInsights retrieves insights data for a specific post
+// GetPostInsights retrieves lifetime insights data for a specific post
 func (c *Client) GetPostInsights(postID string, metrics []string) (*InsightsResponse, error) {
 	params := url.Values{}
 	
 	// Set metrics
 	if len(metrics) == 0 {
-		metrics = []string{
-			"post_impressions",
-			"post_impressions_unique",
-			"post_engaged_users",
-			"post_reactions_by_type_total",
-			"post_clicks",
-			"post_video_views",
-		}
+		metrics = defaultPostMetrics
+	}
+	if err := ValidateMetrics(MetricObjectPost, metrics, PeriodLifetime, c.APIVersion); err != nil {
+		return nil, err
 	}
 	
 	metricsStr := ""
@@ -100,47 +129,15 @@ func (c *Client) GetPostInsights(postID string, metrics []string) (*InsightsResp
 	return &insightsResp, nil
 }
 
-// GetAvailableMetrics retrieves available metrics for insights
+// GetAvailableMetrics returns the cataloged page metrics that exist in the
+// client's API version
 func (c *Client) GetAvailableMetrics(pageID string) ([]string, error) {
-	// Common page metrics for v21.0
-	pageMetrics := []string{
-		"page_fans",
-		"page_fan_adds",
-		"page_fan_removes", 
-		"page_fan_adds_by_likes_source",
-		"page_fan_adds_by_unlikes_source",
-		"page_views_total",
-		"page_views_logged_in_total",
-		"page_views_logged_in_unique",
-		"page_views_external_referrals",
-		"page_impressions",
-		"page_impressions_unique",
-		"page_impressions_paid",
-		"page_impressions_paid_unique",
-		"page_impressions_organic",
-		"page_impressions_organic_unique",
-		"page_impressions_viral",
-		"page_impressions_viral_unique",
-		"page_posts_impressions",
-		"page_posts_impressions_unique",
-		"page_posts_impressions_paid",
-		"page_posts_impressions_paid_unique",
-		"page_posts_impressions_organic",
-		"page_posts_impressions_organic_unique",
-		"page_posts_impressions_viral",
-		"page_posts_impressions_viral_unique",
-		"page_engaged_users",
-		"page_consumptions",
-		"page_consumptions_unique",
-		"page_places_checkin_total",
-		"page_places_checkin_total_unique",
-		"page_places_checkin_mobile",
-		"page_places_checkin_mobile_unique",
-		"page_negative_feedback",
-		"page_negative_feedback_unique",
-		"page_positive_feedback_by_type",
-		"page_positive_feedback_by_type_unique",
+	definitions := MetricsForVersion(MetricObjectPage, c.APIVersion)
+	
+	pageMetrics := make([]string, len(definitions))
+	for i, definition := range definitions {
+		pageMetrics[i] = definition.Name
 	}
 	
 	return pageMetrics, nil
-}
\ No newline at end of file
+}
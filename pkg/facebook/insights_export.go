@@ -0,0 +1,180 @@
+package facebook
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Insights export formats
+const (
+	ExportJSON   = "json"
+	ExportCSV    = "csv"
+	ExportNDJSON = "ndjson"
+)
+
+// validExportFormats lists the formats accepted by the insights endpoints
+var validExportFormats = []string{ExportJSON, ExportCSV, ExportNDJSON}
+
+// csvFlushRows is how many CSV rows are buffered before flushing
+const csvFlushRows = 500
+
+// csvFormulaPrefixes start cells that spreadsheets evaluate as formulas
+const csvFormulaPrefixes = "=+-@\t\r"
+
+// InsightRow is one observation of a metric in long format. Breakdown
+// metrics yield one row per dimension; numeric metrics have no dimension.
+type InsightRow struct {
+	ObjectID  string    `json:"object_id"`
+	Metric    string    `json:"metric"`
+	Period    string    `json:"period"`
+	EndTime   time.Time `json:"end_time"`
+	Dimension string    `json:"dimension,omitempty"`
+	Value     float64   `json:"value"`
+}
+
+// Rows flattens the response into long-format rows. Missing values are
+// skipped and breakdown dimensions are ordered by key.
+func (r *InsightsResponse) Rows(objectID string) []InsightRow {
+	var rows []InsightRow
+	r.eachRow(objectID, func(row InsightRow) error {
+		rows = append(rows, row)
+		return nil
+	})
+	return rows
+}
+
+// eachRow calls fn for every row of the response, stopping at the first error
+func (r *InsightsResponse) eachRow(objectID string, fn func(InsightRow) error) error {
+	for _, insight := range r.Data {
+		for _, value := range insight.Values {
+			row := InsightRow{
+				ObjectID: objectID,
+				Metric:   insight.Name,
+				Period:   insight.Period,
+				EndTime:  value.EndTime.UTC(),
+			}
+
+			if !value.IsBreakdown() {
+				n, ok := toFloat(value.Value)
+				if !ok {
+					continue
+				}
+				row.Value = n
+				if err := fn(row); err != nil {
+					return err
+				}
+				continue
+			}
+
+			breakdown := value.Breakdown()
+			dimensions := make([]string, 0, len(breakdown))
+			for dimension := range breakdown {
+				dimensions = append(dimensions, dimension)
+			}
+			sort.Strings(dimensions)
+
+			for _, dimension := range dimensions {
+				row.Dimension = dimension
+				row.Value = breakdown[dimension]
+				if err := fn(row); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// WriteInsightsCSV writes the response as long-format CSV with the columns
+// object_id, metric, period, end_time, dimension and value, the keys of
+// the NDJSON export. Text cells that a spreadsheet would run as a formula
+// are prefixed with a quote.
+func WriteInsightsCSV(w io.Writer, objectID string, resp *InsightsResponse) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"object_id", "metric", "period", "end_time", "dimension", "value"}); err != nil {
+		return fmt.Errorf("writing csv header: %w", err)
+	}
+
+	written := 0
+	err := resp.eachRow(objectID, func(row InsightRow) error {
+		if err := writer.Write([]string{
+			csvText(row.ObjectID),
+			csvText(row.Metric),
+			csvText(row.Period),
+			row.EndTime.Format(time.RFC3339),
+			csvText(row.Dimension),
+			strconv.FormatFloat(row.Value, 'f', -1, 64),
+		}); err != nil {
+			return err
+		}
+
+		if written++; written%csvFlushRows == 0 {
+			writer.Flush()
+			return writer.Error()
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("writing csv row: %w", err)
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("writing csv: %w", err)
+	}
+	return nil
+}
+
+// csvText guards a text cell against formula injection. Values are written
+// as numbers and keep their sign.
+func csvText(cell string) string {
+	if cell != "" && strings.ContainsRune(csvFormulaPrefixes, rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
+// WriteInsightsNDJSON writes the response as one JSON encoded InsightRow per line
+func WriteInsightsNDJSON(w io.Writer, objectID string, resp *InsightsResponse) error {
+	encoder := json.NewEncoder(w)
+	if err := resp.eachRow(objectID, func(row InsightRow) error {
+		return encoder.Encode(row)
+	}); err != nil {
+		return fmt.Errorf("writing ndjson row: %w", err)
+	}
+	return nil
+}
+
+// isValidExportFormat reports whether format is a known export format
+func isValidExportFormat(format string) bool {
+	for _, valid := range validExportFormats {
+		if format == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// writeInsightsExport streams insights in a CSV or NDJSON response. Once
+// rows have been written an error can only end the response early.
+func writeInsightsExport(w http.ResponseWriter, format string, objectID string, resp *InsightsResponse) error {
+	switch format {
+	case ExportCSV:
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", objectID+"-insights.csv"))
+		w.WriteHeader(http.StatusOK)
+		return WriteInsightsCSV(w, objectID, resp)
+	case ExportNDJSON:
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+		return WriteInsightsNDJSON(w, objectID, resp)
+	}
+	return fmt.Errorf("unsupported export format %q", format)
+}
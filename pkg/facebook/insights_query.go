@@ -0,0 +1,132 @@
+package facebook
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Insights periods
+const (
+	PeriodDay            = "day"
+	PeriodWeek           = "week"
+	PeriodDays28         = "days_28"
+	PeriodMonth          = "month"
+	PeriodLifetime       = "lifetime"
+	PeriodTotalOverRange = "total_over_range"
+)
+
+// validPeriods lists the periods accepted by the insights endpoints
+var validPeriods = []string{
+	PeriodDay, PeriodWeek, PeriodDays28, PeriodMonth, PeriodLifetime, PeriodTotalOverRange,
+}
+
+// InsightsQuery is a parsed insights request
+type InsightsQuery struct {
+	Metrics []string
+	Period  string
+	Since   *time.Time
+	Until   *time.Time
+	// Format is the response format: json (default), csv or ndjson
+	Format string
+}
+
+// InsightsQueryError describes an invalid insights query parameter
+type InsightsQueryError struct {
+	Param  string `json:"param"`
+	Value  string `json:"value"`
+	Reason string `json:"reason"`
+}
+
+func (e *InsightsQueryError) Error() string {
+	return fmt.Sprintf("invalid %s %q: %s", e.Param, e.Value, e.Reason)
+}
+
+// ParseInsightsQuery parses the metric, period, since, until and format
+// parameters of an insights request. Dates may be YYYY-MM-DD, RFC 3339 or Unix seconds.
+func ParseInsightsQuery(query url.Values) (*InsightsQuery, error) {
+	parsed := &InsightsQuery{}
+
+	for _, metric := range strings.Split(query.Get("metric"), ",") {
+		if metric = strings.TrimSpace(metric); metric != "" {
+			parsed.Metrics = append(parsed.Metrics, metric)
+		}
+	}
+
+	if period := query.Get("period"); period != "" {
+		if !isValidPeriod(period) {
+			return nil, &InsightsQueryError{
+				Param:  "period",
+				Value:  period,
+				Reason: "must be one of " + strings.Join(validPeriods, ", "),
+			}
+		}
+		parsed.Period = period
+	}
+
+	parsed.Format = ExportJSON
+	if format := query.Get("format"); format != "" {
+		if !isValidExportFormat(format) {
+			return nil, &InsightsQueryError{
+				Param:  "format",
+				Value:  format,
+				Reason: "must be one of " + strings.Join(validExportFormats, ", "),
+			}
+		}
+		parsed.Format = format
+	}
+
+	var err error
+	if parsed.Since, err = parseInsightsDate("since", query.Get("since")); err != nil {
+		return nil, err
+	}
+	if parsed.Until, err = parseInsightsDate("until", query.Get("until")); err != nil {
+		return nil, err
+	}
+
+	if parsed.Since != nil && parsed.Until != nil && parsed.Until.Before(*parsed.Since) {
+		return nil, &InsightsQueryError{
+			Param:  "until",
+			Value:  query.Get("until"),
+			Reason: "must not be before since",
+		}
+	}
+
+	return parsed, nil
+}
+
+// isValidPeriod reports whether period is a known insights period
+func isValidPeriod(period string) bool {
+	for _, valid := range validPeriods {
+		if period == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// parseInsightsDate parses an insights date parameter; empty values yield nil
+func parseInsightsDate(param, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return &t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	if unix, err := strconv.ParseInt(value, 10, 64); err == nil {
+		t := time.Unix(unix, 0).UTC()
+		return &t, nil
+	}
+
+	return nil, &InsightsQueryError{
+		Param:  param,
+		Value:  value,
+		Reason: "expected YYYY-MM-DD, RFC 3339 or a Unix timestamp",
+	}
+}
@@ -0,0 +1,144 @@
+package facebook
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseInsightsQuery(t *testing.T) {
+	day := func(year int, month time.Month, d int) *time.Time {
+		t := time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		want      InsightsQuery
+		wantParam string
+	}{
+		{
+			name:  "defaults",
+			query: "",
+			want:  InsightsQuery{Format: ExportJSON},
+		},
+		{
+			name:  "metrics and period",
+			query: "metric=page_impressions,+page_follows,,&period=week&format=csv",
+			want:  InsightsQuery{Metrics: []string{"page_impressions", "page_follows"}, Period: PeriodWeek, Format: ExportCSV},
+		},
+		{
+			name:  "YYYY-MM-DD dates",
+			query: "since=2024-03-01&until=2024-03-31",
+			want:  InsightsQuery{Since: day(2024, 3, 1), Until: day(2024, 3, 31), Format: ExportJSON},
+		},
+		{
+			name:  "RFC 3339 dates",
+			query: "since=2024-03-01T00:00:00Z&until=2024-03-02T00:00:00Z",
+			want:  InsightsQuery{Since: day(2024, 3, 1), Until: day(2024, 3, 2), Format: ExportJSON},
+		},
+		{
+			name:  "Unix dates",
+			query: "since=1709251200&until=1709337600",
+			want:  InsightsQuery{Since: day(2024, 3, 1), Until: day(2024, 3, 2), Format: ExportJSON},
+		},
+		{
+			name:  "same day",
+			query: "since=2024-03-01&until=2024-03-01",
+			want:  InsightsQuery{Since: day(2024, 3, 1), Until: day(2024, 3, 1), Format: ExportJSON},
+		},
+		{name: "bad period", query: "period=hourly", wantParam: "period"},
+		{name: "bad format", query: "format=xml", wantParam: "format"},
+		{name: "bad since", query: "since=March+1st", wantParam: "since"},
+		{name: "bad until", query: "until=2024-13-01", wantParam: "until"},
+		{name: "since after until", query: "since=2024-03-02&until=2024-03-01", wantParam: "until"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := ParseInsightsQuery(values)
+			if tt.wantParam != "" {
+				var queryErr *InsightsQueryError
+				if !errors.As(err, &queryErr) || queryErr.Param != tt.wantParam {
+					t.Fatalf("Expected an InsightsQueryError for %s, got %v", tt.wantParam, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if strings.Join(got.Metrics, ",") != strings.Join(tt.want.Metrics, ",") || got.Period != tt.want.Period || got.Format != tt.want.Format {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+			if !sameTime(got.Since, tt.want.Since) || !sameTime(got.Until, tt.want.Until) {
+				t.Errorf("Expected since %v and until %v, got %v and %v", tt.want.Since, tt.want.Until, got.Since, got.Until)
+			}
+		})
+	}
+}
+
+// sameTime reports whether two optional times are both nil or equal
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func TestInsightsQueryErrorResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		t.Errorf("Unexpected request %s", req.URL)
+	}))
+	defer server.Close()
+
+	router := NewRouter("test_token")
+	router.defaultClient.BaseURL = server.URL
+
+	simple := NewSimpleRouter("test_token")
+	simple.defaultClient.BaseURL = server.URL
+
+	handlers := map[string]http.Handler{"Router": router.SetupRoutes(), "SimpleRouter": simple}
+	for name, handler := range handlers {
+		send := func(target string) (int, map[string]interface{}) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest("GET", target, nil))
+			var body map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("%s: expected a JSON error body, got %s", name, rec.Body.String())
+			}
+			return rec.Code, body
+		}
+
+		code, body := send("/api/pages/page_1/insights?since=2024-03-02&until=2024-03-01")
+		if code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", name, code)
+		}
+		if body["param"] != "until" || body["value"] != "2024-03-01" || body["reason"] != "must not be before since" || body["code"] != float64(http.StatusBadRequest) {
+			t.Errorf("%s: unexpected query error body %v", name, body)
+		}
+		if body["error"] != `invalid until "2024-03-01": must not be before since` {
+			t.Errorf("%s: unexpected error message %v", name, body["error"])
+		}
+
+		// Metrics are checked against the catalog before calling Graph
+		code, body = send("/api/pages/page_1/insights?metric=page_fans&period=day")
+		if code != http.StatusBadRequest || body["param"] != "metric" || body["api_version"] != DefaultAPIVersion {
+			t.Errorf("%s: unexpected metric error %d %v", name, code, body)
+		}
+		if problems, ok := body["problems"].([]interface{}); !ok || len(problems) != 1 {
+			t.Errorf("%s: expected one metric problem, got %v", name, body["problems"])
+		}
+	}
+}
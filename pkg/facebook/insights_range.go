@@ -0,0 +1,241 @@
+package facebook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MaxInsightsRange is the longest since/until window Facebook accepts in a
+// single insights request
+const MaxInsightsRange = 90 * 24 * time.Hour
+
+// maxInsightsPages bounds how many paging.next links are followed per chunk
+const maxInsightsPages = 100
+
+// InsightsRangeOptions tunes GetPageInsightsRange
+type InsightsRangeOptions struct {
+	// ChunkSize is the window of each request; defaults to MaxInsightsRange
+	ChunkSize time.Duration
+	// Concurrency is the number of chunks fetched at once (default 1)
+	Concurrency int
+}
+
+// insightsChunk is one since/until window of a ranged insights query
+type insightsChunk struct {
+	since time.Time
+	until time.Time
+}
+
+// GetPageInsightsRange retrieves page insights over an arbitrarily long range
+// by splitting it into windows Facebook accepts, following paging.next within
+// each window, and merging the values into one series per metric and period
+// ordered by end_time without duplicates. Metrics are validated against the
+// catalog as in GetPageInsights.
+func (c *Client) GetPageInsightsRange(ctx context.Context, pageID string, metrics []string, period string, since, until time.Time, opts *InsightsRangeOptions) (*InsightsResponse, error) {
+	if opts == nil {
+		opts = &InsightsRangeOptions{}
+	}
+	if !until.After(since) {
+		return nil, fmt.Errorf("insights range: until must be after since")
+	}
+
+	metrics, period = pageInsightsDefaults(metrics, period)
+	if err := ValidateMetrics(MetricObjectPage, metrics, period, c.APIVersion); err != nil {
+		return nil, err
+	}
+
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 || chunkSize > MaxInsightsRange {
+		chunkSize = MaxInsightsRange
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	chunks := splitInsightsRange(since, until, chunkSize)
+	results := make([][]Insight, len(chunks))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	sem := make(chan struct{}, concurrency)
+
+	for i, chunk := range chunks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, chunk insightsChunk) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			insights, err := c.fetchInsightsChunk(ctx, pageID, metrics, period, chunk)
+			if err != nil {
+				once.Do(func() {
+					firstErr = fmt.Errorf("insights %s to %s: %w",
+						chunk.since.Format("2006-01-02"), chunk.until.Format("2006-01-02"), err)
+					cancel()
+				})
+				return
+			}
+			results[i] = insights
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return &InsightsResponse{Data: mergeInsights(results)}, nil
+}
+
+// splitInsightsRange splits [since, until] into consecutive windows of at most size
+func splitInsightsRange(since, until time.Time, size time.Duration) []insightsChunk {
+	var chunks []insightsChunk
+	for start := since; start.Before(until); start = start.Add(size) {
+		end := start.Add(size)
+		if end.After(until) {
+			end = until
+		}
+		chunks = append(chunks, insightsChunk{since: start, until: end})
+	}
+	return chunks
+}
+
+// fetchInsightsChunk fetches one window and follows paging.next links that
+// stay within it. Facebook keeps offering next windows beyond until, so links
+// whose since lies past the window are not followed.
+func (c *Client) fetchInsightsChunk(ctx context.Context, pageID string, metrics []string, period string, chunk insightsChunk) ([]Insight, error) {
+	resp, err := c.getPageInsights(ctx, pageID, metrics, period, &chunk.since, &chunk.until)
+	if err != nil {
+		return nil, err
+	}
+
+	insights := resp.Data
+	next := resp.Paging.Next
+	for page := 0; next != "" && page < maxInsightsPages; page++ {
+		if !pagingWithin(next, chunk.until) {
+			break
+		}
+
+		var nextResp InsightsResponse
+		if err := c.getPagingURL(ctx, next, &nextResp); err != nil {
+			return nil, err
+		}
+		if len(nextResp.Data) == 0 {
+			break
+		}
+
+		insights = append(insights, nextResp.Data...)
+		next = nextResp.Paging.Next
+	}
+
+	return insights, nil
+}
+
+// pagingWithin reports whether a paging link starts before until
+func pagingWithin(link string, until time.Time) bool {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+
+	since := parsed.Query().Get("since")
+	if since == "" {
+		return true
+	}
+
+	if unix, err := strconv.ParseInt(since, 10, 64); err == nil {
+		return time.Unix(unix, 0).Before(until)
+	}
+	if t, err := time.Parse("2006-01-02", since); err == nil {
+		return t.Before(until)
+	}
+	return false
+}
+
+// getPagingURL fetches an absolute paging URL returned by the Graph API.
+// Paging links already carry the access token.
+func (c *Client) getPagingURL(ctx context.Context, link string, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", link, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("making request: %w", err)
+	}
+
+	return c.handleResponse(resp, result)
+}
+
+// mergeInsights combines insights of consecutive windows into one insight per
+// metric and period whose values are ordered by end_time without duplicates.
+// Metrics keep the order in which they first appear.
+func mergeInsights(chunks [][]Insight) []Insight {
+	type mergeKey struct {
+		name   string
+		period string
+	}
+
+	var order []mergeKey
+	merged := make(map[mergeKey]*Insight)
+	seen := make(map[mergeKey]map[int64]bool)
+
+	for _, insights := range chunks {
+		for _, insight := range insights {
+			key := mergeKey{name: insight.Name, period: insight.Period}
+			target, ok := merged[key]
+			if !ok {
+				copied := insight
+				copied.Values = nil
+				target = &copied
+				merged[key] = target
+				seen[key] = make(map[int64]bool)
+				order = append(order, key)
+			}
+
+			for _, value := range insight.Values {
+				endTime := value.EndTime.Unix()
+				if seen[key][endTime] {
+					continue
+				}
+				seen[key][endTime] = true
+				target.Values = append(target.Values, value)
+			}
+		}
+	}
+
+	result := make([]Insight, 0, len(order))
+	for _, key := range order {
+		insight := merged[key]
+		sort.SliceStable(insight.Values, func(i, j int) bool {
+			return insight.Values[i].EndTime.Before(insight.Values[j].EndTime.Time)
+		})
+		result = append(result, *insight)
+	}
+
+	return result
+}
@@ -0,0 +1,217 @@
+package facebook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGetPageInsightsRangeChunksAndMerges(t *testing.T) {
+	var mu sync.Mutex
+	var windows []string
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+		since, _ := time.Parse("2006-01-02", query.Get("since"))
+		until, _ := time.Parse("2006-01-02", query.Get("until"))
+
+		mu.Lock()
+		windows = append(windows, query.Get("since")+"/"+query.Get("until"))
+		mu.Unlock()
+
+		if until.Sub(since) > MaxInsightsRange {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"message":"range too long","code":100}}`))
+			return
+		}
+
+		// One value per day, inclusive of both ends so chunk boundaries overlap
+		var values []string
+		for day := since; !day.After(until); day = day.AddDate(0, 0, 1) {
+			values = append(values, fmt.Sprintf(`{"value":1,"end_time":"%s"}`, day.Format("2006-01-02T15:04:05-0700")))
+		}
+
+		// Offer a next window beyond until, which must not be followed
+		next := fmt.Sprintf("%s/v23.0/page/insights?since=%d&until=%d", server.URL, until.Unix(), until.AddDate(0, 0, 90).Unix())
+		fmt.Fprintf(w, `{"data":[{"name":"page_impressions","period":"day","values":[%s]}],"paging":{"next":%q}}`,
+			strings.Join(values, ","), next)
+	}))
+	defer server.Close()
+
+	client := NewClient("test_token")
+	client.BaseURL = server.URL
+
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := since.AddDate(0, 0, 200)
+
+	resp, err := client.GetPageInsightsRange(context.Background(), "page", []string{"page_impressions"}, "day", since, until,
+		&InsightsRangeOptions{Concurrency: 3})
+	if err != nil {
+		t.Fatalf("Expected ranged insights to succeed, got %v", err)
+	}
+
+	if len(windows) != 3 {
+		t.Errorf("Expected 3 requests for a 200 day range, got %v", windows)
+	}
+	if len(resp.Data) != 1 {
+		t.Fatalf("Expected a single merged metric, got %d", len(resp.Data))
+	}
+
+	values := resp.Data[0].Values
+	if len(values) != 201 {
+		t.Fatalf("Expected 201 daily values without duplicates, got %d", len(values))
+	}
+	for i := 1; i < len(values); i++ {
+		if values[i].EndTime.Sub(values[i-1].EndTime.Time) != 24*time.Hour {
+			t.Fatalf("Expected a continuous daily series, gap at %v", values[i].EndTime)
+		}
+	}
+
+	// GetPageInsights delegates long ranges to the chunked variant
+	if _, err := client.GetPageInsights("page", nil, "day", &since, &until); err != nil {
+		t.Errorf("Expected GetPageInsights to chunk long ranges, got %v", err)
+	}
+
+}
+
+func TestGetPageInsightsChunksOpenEndedRange(t *testing.T) {
+	var mu sync.Mutex
+	var windows []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		windows = append(windows, req.URL.Query().Get("since")+"/"+req.URL.Query().Get("until"))
+		mu.Unlock()
+		w.Write([]byte(`{"data":[]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test_token")
+	client.BaseURL = server.URL
+
+	// Without until the range runs until now
+	since := time.Now().AddDate(0, 0, -200)
+	if _, err := client.GetPageInsights("page", nil, "day", &since, nil); err != nil {
+		t.Fatalf("Expected an open-ended range to succeed, got %v", err)
+	}
+	if len(windows) != 3 {
+		t.Fatalf("Expected 3 requests for 200 days until now, got %v", windows)
+	}
+	for _, window := range windows {
+		if strings.HasSuffix(window, "/") {
+			t.Errorf("Expected every chunk to be bounded, got %v", windows)
+		}
+	}
+}
+
+func TestInsightSeriesDecodesByShape(t *testing.T) {
+	var resp InsightsResponse
+	body := `{"data":[
+		{"name":"page_impressions","period":"day","values":[{"value":12,"end_time":"2024-01-02T08:00:00+0000"},{"value":null,"end_time":"2024-01-03T08:00:00+0000"}]},
+		{"name":"post_reactions_by_type_total","period":"lifetime","values":[{"value":{"like":3,"love":2},"end_time":"2024-01-02T08:00:00+0000"}]}
+	]}`
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		t.Fatalf("Decoding insights: %v", err)
+	}
+
+	if got := resp.Data[0].Values[0].Int(); got != 12 {
+		t.Errorf("Expected Int() 12, got %d", got)
+	}
+	reactions := resp.Data[1].Values[0]
+	if got := reactions.Breakdown(); got["like"] != 3 || got["love"] != 2 {
+		t.Errorf("Expected like=3 love=2, got %v", got)
+	}
+	if got := reactions.Float(); got != 5 {
+		t.Errorf("Expected breakdown total 5, got %v", got)
+	}
+
+	series, err := resp.Series()
+	if err != nil {
+		t.Fatalf("Expected series to decode, got %v", err)
+	}
+	if series[0].Shape != MetricShapeNumber || len(series[0].Points) != 2 || series[0].Points[0].Value != 12 {
+		t.Errorf("Unexpected number series %+v", series[0])
+	}
+	if series[1].Shape != MetricShapeBreakdown || series[1].Points[0].Breakdown["love"] != 2 {
+		t.Errorf("Unexpected breakdown series %+v", series[1])
+	}
+
+	// A breakdown where the catalog expects a number is rejected
+	mismatched := Insight{Name: "page_impressions", Values: []Value{{Value: map[string]interface{}{"a": 1.0}}}}
+	if _, err := mismatched.Series(); err == nil {
+		t.Error("Expected a shape mismatch error")
+	}
+}
+
+func TestValidateMetricsAgainstCatalog(t *testing.T) {
+	err := ValidateMetrics(MetricObjectPage, []string{"page_fans", "page_impressions", "page_brand_new_metric"}, PeriodDay, "v23.0")
+	var metricErr *MetricError
+	if !errors.As(err, &metricErr) || !errors.Is(err, ErrInvalidMetric) {
+		t.Fatalf("Expected a MetricError, got %v", err)
+	}
+	if len(metricErr.Problems) != 1 || metricErr.Problems[0].Metric != "page_fans" || metricErr.Problems[0].ReplacedBy != "page_follows" {
+		t.Errorf("Unexpected problems %+v", metricErr.Problems)
+	}
+
+	// Deprecated metrics are still valid in the versions that had them
+	if err := ValidateMetrics(MetricObjectPage, []string{"page_fans"}, PeriodDay, "v17.0"); err != nil {
+		t.Errorf("Expected page_fans to be valid in v17.0, got %v", err)
+	}
+	if err := ValidateMetrics(MetricObjectPage, []string{"page_follows"}, PeriodWeek, "v23.0"); err == nil {
+		t.Error("Expected an unsupported period error")
+	}
+	if err := ValidateMetrics(MetricObjectPage, []string{"post_clicks"}, PeriodLifetime, "v23.0"); err == nil {
+		t.Error("Expected an object type error")
+	}
+
+	// Invalid combinations fail before any request is made
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		t.Errorf("Unexpected request %s", req.URL)
+	}))
+	defer server.Close()
+
+	client := NewClient("token")
+	client.BaseURL = server.URL
+	if _, err := client.GetPageInsights("page", []string{"page_engaged_users"}, "", nil, nil); !errors.Is(err, ErrInvalidMetric) {
+		t.Errorf("Expected ErrInvalidMetric, got %v", err)
+	}
+}
+
+func TestWriteInsightsCSVLongFormat(t *testing.T) {
+	var resp InsightsResponse
+	body := `{"data":[
+		{"name":"page_impressions","period":"day","values":[{"value":12,"end_time":"2024-01-02T08:00:00+0000"},{"value":null,"end_time":"2024-01-03T08:00:00+0000"}]},
+		{"name":"page_actions_post_reactions_total","period":"day","values":[{"value":{"love":2,"like":3},"end_time":"2024-01-02T08:00:00+0000"}]},
+		{"name":"page_fans_city","period":"day","values":[{"value":{"=HYPERLINK(\"x\")":1,"-5":-2},"end_time":"2024-01-02T08:00:00+0000"}]}
+	]}`
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		t.Fatalf("Decoding insights: %v", err)
+	}
+
+	var out strings.Builder
+	if err := WriteInsightsCSV(&out, "123", &resp); err != nil {
+		t.Fatalf("Expected CSV export to succeed, got %v", err)
+	}
+
+	// Dimensions that look like formulas are quoted, negative values are not
+	expected := "object_id,metric,period,end_time,dimension,value\n" +
+		"123,page_impressions,day,2024-01-02T08:00:00Z,,12\n" +
+		"123,page_actions_post_reactions_total,day,2024-01-02T08:00:00Z,like,3\n" +
+		"123,page_actions_post_reactions_total,day,2024-01-02T08:00:00Z,love,2\n" +
+		"123,page_fans_city,day,2024-01-02T08:00:00Z,'-5,-2\n" +
+		"123,page_fans_city,day,2024-01-02T08:00:00Z,\"'=HYPERLINK(\"\"x\"\")\",1\n"
+	if out.String() != expected {
+		t.Errorf("Unexpected CSV:\n%s", out.String())
+	}
+
+	if _, err := ParseInsightsQuery(map[string][]string{"format": {"xlsx"}}); err == nil {
+		t.Error("Expected an unsupported format error")
+	}
+}
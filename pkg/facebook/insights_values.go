@@ -0,0 +1,175 @@
+package facebook
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Int returns a numeric insight value rounded to an integer, or the sum of a
+// breakdown. Values of any other shape yield 0.
+func (v Value) Int() int64 {
+	return int64(math.Round(v.Float()))
+}
+
+// Float returns a numeric insight value, or the sum of a breakdown.
+// Values of any other shape yield 0.
+func (v Value) Float() float64 {
+	if n, ok := toFloat(v.Value); ok {
+		return n
+	}
+
+	total := 0.0
+	for _, n := range v.Breakdown() {
+		total += n
+	}
+	return total
+}
+
+// Breakdown returns the numeric entries of a breakdown value such as
+// post_reactions_by_type_total or page_fans_country. Non-numeric entries are
+// skipped; values that are not breakdowns yield nil.
+func (v Value) Breakdown() map[string]float64 {
+	values, ok := v.Value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	breakdown := make(map[string]float64, len(values))
+	for key, raw := range values {
+		if n, ok := toFloat(raw); ok {
+			breakdown[key] = n
+		}
+	}
+	return breakdown
+}
+
+// IsBreakdown reports whether the value is a breakdown rather than a number
+func (v Value) IsBreakdown() bool {
+	_, ok := v.Value.(map[string]interface{})
+	return ok
+}
+
+// toFloat converts the numeric representations found in decoded insights
+func toFloat(raw interface{}) (float64, bool) {
+	switch n := raw.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// MetricSeries is an insight decoded according to its metric shape
+type MetricSeries struct {
+	Name   string        `json:"name"`
+	Period string        `json:"period"`
+	Title  string        `json:"title,omitempty"`
+	Shape  MetricShape   `json:"shape"`
+	Points []SeriesPoint `json:"points"`
+}
+
+// SeriesPoint is one value of a MetricSeries. Breakdown is set for
+// breakdown metrics, in which case Value is the sum of its entries.
+type SeriesPoint struct {
+	EndTime   time.Time          `json:"end_time"`
+	Value     float64            `json:"value"`
+	Breakdown map[string]float64 `json:"breakdown,omitempty"`
+}
+
+// Keys returns the breakdown keys of all points, sorted
+func (s *MetricSeries) Keys() []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, point := range s.Points {
+		for key := range point.Breakdown {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Series decodes the insight into a typed series. The shape comes from the
+// metric catalog, or is inferred from the values for unknown metrics; values
+// that do not match a cataloged shape are reported as an error.
+func (i *Insight) Series() (*MetricSeries, error) {
+	shape := i.Shape()
+
+	series := &MetricSeries{
+		Name:   i.Name,
+		Period: i.Period,
+		Title:  i.Title,
+		Shape:  shape,
+		Points: make([]SeriesPoint, 0, len(i.Values)),
+	}
+
+	for _, value := range i.Values {
+		point := SeriesPoint{EndTime: value.EndTime.Time}
+
+		switch {
+		case value.Value == nil:
+			// Facebook reports missing days as null
+		case shape == MetricShapeBreakdown && value.IsBreakdown():
+			point.Breakdown = value.Breakdown()
+			point.Value = value.Float()
+		case shape == MetricShapeNumber && !value.IsBreakdown():
+			n, ok := toFloat(value.Value)
+			if !ok {
+				return nil, fmt.Errorf("metric %s: unexpected value %v at %s", i.Name, value.Value, value.EndTime.Format(time.RFC3339))
+			}
+			point.Value = n
+		default:
+			return nil, fmt.Errorf("metric %s: expected %s value, got %T at %s", i.Name, shape, value.Value, value.EndTime.Format(time.RFC3339))
+		}
+
+		series.Points = append(series.Points, point)
+	}
+
+	return series, nil
+}
+
+// Shape returns the cataloged shape of the metric, inferring it from the
+// values for metrics not in the catalog
+func (i *Insight) Shape() MetricShape {
+	if definition, ok := LookupMetric(i.Name); ok {
+		return definition.Shape
+	}
+
+	for _, value := range i.Values {
+		if value.IsBreakdown() {
+			return MetricShapeBreakdown
+		}
+	}
+	return MetricShapeNumber
+}
+
+// Series decodes every insight of the response into typed series
+func (r *InsightsResponse) Series() ([]MetricSeries, error) {
+	series := make([]MetricSeries, 0, len(r.Data))
+	for i := range r.Data {
+		s, err := r.Data[i].Series()
+		if err != nil {
+			return nil, err
+		}
+		series = append(series, *s)
+	}
+	return series, nil
+}
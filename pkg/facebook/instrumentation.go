@@ -0,0 +1,163 @@
+package facebook
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// graphIDSegment matches path segments that are object IDs, e.g. 1234 or 1234_5678
+var graphIDSegment = regexp.MustCompile(`^[0-9]+(_[0-9]+)?$`)
+
+// graphVersionSegment matches API version path segments such as v23.0
+var graphVersionSegment = regexp.MustCompile(`^v[0-9]+\.[0-9]+$`)
+
+// graphEndpointLabel turns a Graph request path into a low-cardinality
+// label by dropping the API version and replacing object IDs with {id}
+func graphEndpointLabel(path string) string {
+	var segments []string
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		switch {
+		case graphVersionSegment.MatchString(segment):
+			continue
+		case graphIDSegment.MatchString(segment):
+			segment = "{id}"
+		}
+		segments = append(segments, segment)
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// observeGraphRequest records the latency and outcome of a Graph call, up
+// to the response headers, and the rate-limit usage reported with it
+func (c *Client) observeGraphRequest(req *http.Request, resp *http.Response, elapsed time.Duration) {
+	if c.Metrics == nil {
+		return
+	}
+
+	endpoint := graphEndpointLabel(req.URL.Path)
+	status := "error"
+	if resp != nil {
+		status = strconv.Itoa(resp.StatusCode)
+		c.observeRateLimits(resp.Header)
+	}
+
+	c.Metrics.AddCounter("facebook_graph_requests_total", "Graph API requests by endpoint, method and status",
+		Labels{"endpoint": endpoint, "method": req.Method, "status": status}, 1)
+	c.Metrics.Observe("facebook_graph_request_duration_seconds", "Graph API latency until response headers",
+		Labels{"endpoint": endpoint, "method": req.Method}, elapsed.Seconds())
+}
+
+// observeGraphError counts a Graph error response by endpoint and error code
+func (c *Client) observeGraphError(req *http.Request, apiErr *APIError) {
+	if c.Metrics == nil || req == nil {
+		return
+	}
+
+	c.Metrics.AddCounter("facebook_graph_errors_total", "Graph API error responses by endpoint and error code",
+		Labels{"endpoint": graphEndpointLabel(req.URL.Path), "code": strconv.Itoa(apiErr.Code)}, 1)
+}
+
+// recordRetry counts a retried Graph operation
+func (c *Client) recordRetry(operation string) {
+	c.Metrics.AddCounter("facebook_graph_retries_total", "Retried Graph API operations",
+		Labels{"operation": operation}, 1)
+}
+
+// recordDeliveryRetry counts a retried event delivery to a sink
+func (r *MetricsRegistry) recordDeliveryRetry(sink string) {
+	r.AddCounter("event_delivery_retries_total", "Retried event deliveries by sink",
+		Labels{"sink": sink}, 1)
+}
+
+// rateLimitUsage is the usage object of X-App-Usage and X-Page-Usage, in
+// percent of the limit
+type rateLimitUsage struct {
+	CallCount    *float64 `json:"call_count"`
+	TotalCPUTime *float64 `json:"total_cputime"`
+	TotalTime    *float64 `json:"total_time"`
+}
+
+// observeRateLimits exports the usage percentages of the X-App-Usage,
+// X-Page-Usage and X-Business-Use-Case-Usage headers
+func (c *Client) observeRateLimits(header http.Header) {
+	for _, scope := range []struct{ name, header string }{
+		{"app", "X-App-Usage"},
+		{"page", "X-Page-Usage"},
+	} {
+		var usage rateLimitUsage
+		if value := header.Get(scope.header); value == "" || json.Unmarshal([]byte(value), &usage) != nil {
+			continue
+		}
+		c.setRateLimitUsage(scope.name, "", usage)
+	}
+
+	// Business use case usage is keyed by business ID
+	var business map[string][]struct {
+		Type string `json:"type"`
+		rateLimitUsage
+	}
+	if value := header.Get("X-Business-Use-Case-Usage"); value == "" || json.Unmarshal([]byte(value), &business) != nil {
+		return
+	}
+	for id, usages := range business {
+		for _, usage := range usages {
+			c.setRateLimitUsage("business_"+usage.Type, id, usage.rateLimitUsage)
+		}
+	}
+}
+
+// setRateLimitUsage sets the usage gauges of one rate-limit scope
+func (c *Client) setRateLimitUsage(scope, id string, usage rateLimitUsage) {
+	for _, metric := range []struct {
+		name  string
+		value *float64
+	}{
+		{"call_count", usage.CallCount},
+		{"total_cputime", usage.TotalCPUTime},
+		{"total_time", usage.TotalTime},
+	} {
+		if metric.value == nil {
+			continue
+		}
+		c.Metrics.SetGauge("facebook_graph_rate_limit_usage_percent", "Graph API rate-limit usage reported by Facebook",
+			Labels{"scope": scope, "id": id, "metric": metric.name}, *metric.value)
+	}
+}
+
+// statusRecorder captures the status code written by an HTTP handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// observeHTTPRequest records a request served by one of the routers
+func (r *MetricsRegistry) observeHTTPRequest(route, method string, status int, elapsed time.Duration) {
+	r.AddCounter("http_requests_total", "HTTP requests served by route, method and status",
+		Labels{"route": route, "method": method, "status": strconv.Itoa(status)}, 1)
+	r.Observe("http_request_duration_seconds", "HTTP request latency by route and method",
+		Labels{"route": route, "method": method}, elapsed.Seconds())
+}
+
+// recordCacheLookup counts a hit or miss of an in-memory cache
+func (r *MetricsRegistry) recordCacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	r.AddCounter("cache_lookups_total", "In-memory cache lookups by cache and result",
+		Labels{"cache": cache, "result": result}, 1)
+}
@@ -0,0 +1,226 @@
+package facebook
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"  // register GIF decoder for image.DecodeConfig
+	_ "image/jpeg" // register JPEG decoder for image.DecodeConfig
+	_ "image/png"  // register PNG decoder for image.DecodeConfig
+	"net/http"
+)
+
+// Media validation errors, matched with errors.Is
+var (
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	ErrMediaTooLarge        = errors.New("media too large")
+	ErrInvalidDimensions    = errors.New("invalid image dimensions")
+	ErrCorruptMedia         = errors.New("corrupt or truncated media")
+)
+
+// MediaValidationError describes why a media file was rejected before upload
+type MediaValidationError struct {
+	Err         error
+	ContentType string
+	Detail      string
+}
+
+func (e *MediaValidationError) Error() string {
+	if e.Detail == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%v: %s", e.Err, e.Detail)
+}
+
+// Unwrap returns the sentinel error describing the failure
+func (e *MediaValidationError) Unwrap() error {
+	return e.Err
+}
+
+// PhotoLimits bounds the photos accepted for upload
+type PhotoLimits struct {
+	MaxBytes     int64
+	MaxDimension int
+	MinDimension int
+}
+
+// DefaultPhotoLimits reflects Facebook's documented 4 MB photo size limit
+// and guards against images too large to be processed
+var DefaultPhotoLimits = PhotoLimits{
+	MaxBytes:     4 << 20,
+	MaxDimension: 10000,
+	MinDimension: 1,
+}
+
+// sniffLen is how much of a file is inspected to detect type and dimensions
+const sniffLen = 64 * 1024
+
+// imageExtensions maps supported photo MIME types to file extensions
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+	"image/bmp":  ".bmp",
+	"image/tiff": ".tiff",
+}
+
+// MediaInfo describes a sniffed media file
+type MediaInfo struct {
+	ContentType string `json:"content_type"`
+	Extension   string `json:"extension"`
+	Width       int    `json:"width,omitempty"`
+	Height      int    `json:"height,omitempty"`
+	Size        int64  `json:"size,omitempty"`
+}
+
+// Filename returns a file name with the extension matching the content type
+func (m *MediaInfo) Filename(base string) string {
+	return base + m.Extension
+}
+
+// DetectImageType sniffs the MIME type of an image from its leading bytes
+func DetectImageType(header []byte) (string, error) {
+	contentType := http.DetectContentType(header)
+
+	// net/http does not sniff TIFF
+	if contentType == "application/octet-stream" &&
+		(bytes.HasPrefix(header, []byte("II*\x00")) || bytes.HasPrefix(header, []byte("MM\x00*"))) {
+		contentType = "image/tiff"
+	}
+
+	if _, ok := imageExtensions[contentType]; !ok {
+		return contentType, &MediaValidationError{
+			Err:         ErrUnsupportedMediaType,
+			ContentType: contentType,
+			Detail:      fmt.Sprintf("%s is not a supported photo format", contentType),
+		}
+	}
+
+	return contentType, nil
+}
+
+// InspectImage detects the type and dimensions of an image from its leading
+// bytes. size is the full file size, or -1 if unknown.
+func InspectImage(header []byte, size int64) (*MediaInfo, error) {
+	contentType, err := DetectImageType(header)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &MediaInfo{
+		ContentType: contentType,
+		Extension:   imageExtensions[contentType],
+		Size:        size,
+	}
+
+	info.Width, info.Height, err = imageDimensions(contentType, header)
+	if err != nil {
+		return nil, &MediaValidationError{
+			Err:         ErrCorruptMedia,
+			ContentType: contentType,
+			Detail:      err.Error(),
+		}
+	}
+
+	return info, nil
+}
+
+// imageDimensions reads the dimensions from an image header. Formats whose
+// dimensions cannot be read from the header return zero dimensions.
+func imageDimensions(contentType string, header []byte) (int, int, error) {
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif":
+		config, _, err := image.DecodeConfig(bytes.NewReader(header))
+		if err != nil {
+			// Large JPEG metadata can push the frame header past the sniffed
+			// bytes; only a truncated full file is treated as corrupt.
+			if contentType == "image/jpeg" && len(header) >= sniffLen {
+				return 0, 0, nil
+			}
+			return 0, 0, fmt.Errorf("reading %s header: %w", contentType, err)
+		}
+		return config.Width, config.Height, nil
+	case "image/webp":
+		return webpDimensions(header)
+	case "image/bmp":
+		if len(header) < 26 {
+			return 0, 0, fmt.Errorf("bmp header too short")
+		}
+		width := int32(binary.LittleEndian.Uint32(header[18:22]))
+		height := int32(binary.LittleEndian.Uint32(header[22:26]))
+		if height < 0 {
+			height = -height
+		}
+		return int(width), int(height), nil
+	}
+	return 0, 0, nil
+}
+
+// webpDimensions parses the VP8, VP8L and VP8X headers of a WebP file
+func webpDimensions(header []byte) (int, int, error) {
+	if len(header) < 30 {
+		return 0, 0, fmt.Errorf("webp header too short")
+	}
+
+	chunk := header[12:30]
+	switch string(chunk[:4]) {
+	case "VP8 ":
+		// Frame tag (3 bytes) and start code (3 bytes) precede 14-bit dimensions
+		if !bytes.Equal(chunk[11:14], []byte{0x9d, 0x01, 0x2a}) {
+			return 0, 0, fmt.Errorf("invalid VP8 start code")
+		}
+		width := int(binary.LittleEndian.Uint16(chunk[14:16]) & 0x3fff)
+		height := int(binary.LittleEndian.Uint16(chunk[16:18]) & 0x3fff)
+		return width, height, nil
+	case "VP8L":
+		if chunk[8] != 0x2f {
+			return 0, 0, fmt.Errorf("invalid VP8L signature")
+		}
+		bits := binary.LittleEndian.Uint32(chunk[9:13])
+		width := int(bits&0x3fff) + 1
+		height := int((bits>>14)&0x3fff) + 1
+		return width, height, nil
+	case "VP8X":
+		width := int(uint32(chunk[12])|uint32(chunk[13])<<8|uint32(chunk[14])<<16) + 1
+		height := int(uint32(chunk[15])|uint32(chunk[16])<<8|uint32(chunk[17])<<16) + 1
+		return width, height, nil
+	}
+	return 0, 0, fmt.Errorf("unknown webp chunk %q", chunk[:4])
+}
+
+// Validate checks the media against the given limits
+func (m *MediaInfo) Validate(limits PhotoLimits) error {
+	if limits.MaxBytes > 0 && m.Size > limits.MaxBytes {
+		return &MediaValidationError{
+			Err:         ErrMediaTooLarge,
+			ContentType: m.ContentType,
+			Detail:      fmt.Sprintf("%d bytes exceeds the %d byte limit", m.Size, limits.MaxBytes),
+		}
+	}
+
+	// Dimensions are unknown for some formats
+	if m.Width == 0 && m.Height == 0 {
+		return nil
+	}
+
+	if limits.MaxDimension > 0 && (m.Width > limits.MaxDimension || m.Height > limits.MaxDimension) {
+		return &MediaValidationError{
+			Err:         ErrInvalidDimensions,
+			ContentType: m.ContentType,
+			Detail:      fmt.Sprintf("%dx%d exceeds %dpx per side", m.Width, m.Height, limits.MaxDimension),
+		}
+	}
+
+	if m.Width < limits.MinDimension || m.Height < limits.MinDimension {
+		return &MediaValidationError{
+			Err:         ErrInvalidDimensions,
+			ContentType: m.ContentType,
+			Detail:      fmt.Sprintf("%dx%d is smaller than %dpx per side", m.Width, m.Height, limits.MinDimension),
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,285 @@
+package facebook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func encodePNG(t *testing.T, width, height int) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatalf("Encoding PNG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestInspectImage(t *testing.T) {
+	info, err := InspectImage(encodePNG(t, 10, 20), 100)
+	if err != nil {
+		t.Fatalf("Expected PNG to be accepted, got %v", err)
+	}
+	if info.ContentType != "image/png" || info.Filename("image") != "image.png" {
+		t.Errorf("Expected image/png with .png extension, got %s %s", info.ContentType, info.Extension)
+	}
+	if info.Width != 10 || info.Height != 20 {
+		t.Errorf("Expected 10x20, got %dx%d", info.Width, info.Height)
+	}
+
+	var buf bytes.Buffer
+	if err := gif.Encode(&buf, image.NewPaletted(image.Rect(0, 0, 3, 4), color.Palette{color.Black, color.White}), nil); err != nil {
+		t.Fatalf("Encoding GIF: %v", err)
+	}
+	info, err = InspectImage(buf.Bytes(), int64(buf.Len()))
+	if err != nil || info.ContentType != "image/gif" || info.Width != 3 {
+		t.Errorf("Expected 3x4 GIF, got %+v (%v)", info, err)
+	}
+}
+
+func TestInspectImageRejectsInvalidMedia(t *testing.T) {
+	_, err := InspectImage([]byte("this is not an image"), 20)
+	if !errors.Is(err, ErrUnsupportedMediaType) {
+		t.Errorf("Expected ErrUnsupportedMediaType, got %v", err)
+	}
+
+	truncated := encodePNG(t, 10, 10)[:20]
+	_, err = InspectImage(truncated, int64(len(truncated)))
+	if !errors.Is(err, ErrCorruptMedia) {
+		t.Errorf("Expected ErrCorruptMedia, got %v", err)
+	}
+
+	info := &MediaInfo{ContentType: "image/png", Width: 20000, Height: 10, Size: 10}
+	if err := info.Validate(DefaultPhotoLimits); !errors.Is(err, ErrInvalidDimensions) {
+		t.Errorf("Expected ErrInvalidDimensions, got %v", err)
+	}
+
+	info = &MediaInfo{ContentType: "image/png", Width: 10, Height: 10, Size: DefaultPhotoLimits.MaxBytes + 1}
+	if err := info.Validate(DefaultPhotoLimits); !errors.Is(err, ErrMediaTooLarge) {
+		t.Errorf("Expected ErrMediaTooLarge, got %v", err)
+	}
+}
+
+func TestUploadPhotoFromReaderUsesDetectedType(t *testing.T) {
+	var filename, contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		file, header, err := req.FormFile("source")
+		if err != nil {
+			t.Errorf("Expected source file in upload: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		file.Close()
+		filename = header.Filename
+		contentType = header.Header.Get("Content-Type")
+		w.Write([]byte(`{"id":"photo_1","post_id":"page_1"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test_token")
+	client.BaseURL = server.URL
+
+	resp, err := client.UploadPhotoFromReader("page", bytes.NewReader(encodePNG(t, 5, 5)), "hello", true)
+	if err != nil {
+		t.Fatalf("Expected upload to succeed, got %v", err)
+	}
+	if resp.ID != "photo_1" {
+		t.Errorf("Expected photo ID photo_1, got %s", resp.ID)
+	}
+	if filename != "image.png" || contentType != "image/png" {
+		t.Errorf("Expected image.png with image/png, got %s with %s", filename, contentType)
+	}
+}
+
+func TestUploadPhotoStreamsWithContentLength(t *testing.T) {
+	var contentLength, received int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		contentLength = req.ContentLength
+		body, _ := io.ReadAll(req.Body)
+		received = int64(len(body))
+		w.Write([]byte(`{"id":"photo_1"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test_token")
+	client.BaseURL = server.URL
+
+	image := encodePNG(t, 200, 200)
+	var lastSent, lastTotal int64
+	opts := &UploadOptions{
+		Size: int64(len(image)),
+		Progress: func(sent, total int64) {
+			lastSent, lastTotal = sent, total
+		},
+	}
+
+	if _, err := client.UploadPhotoFromReaderContext(context.Background(), "page", bytes.NewReader(image), "", true, opts); err != nil {
+		t.Fatalf("Expected upload to succeed, got %v", err)
+	}
+	if contentLength <= 0 || contentLength != received {
+		t.Errorf("Expected Content-Length %d to match body size %d", contentLength, received)
+	}
+	if lastSent != int64(len(image)) || lastTotal != int64(len(image)) {
+		t.Errorf("Expected final progress %d/%d, got %d/%d", len(image), len(image), lastSent, lastTotal)
+	}
+}
+
+func TestUploadPhotoEnforcesLimitWhileStreaming(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		io.Copy(io.Discard, req.Body)
+		w.Write([]byte(`{"id":"photo_1"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test_token")
+	client.BaseURL = server.URL
+
+	// Pad a valid PNG beyond the sniff buffer so the size is only discovered while streaming
+	image := append(encodePNG(t, 10, 10), make([]byte, 2*sniffLen)...)
+	opts := &UploadOptions{Limits: &PhotoLimits{MaxBytes: int64(sniffLen + 10), MaxDimension: 100, MinDimension: 1}}
+
+	_, err := client.UploadPhotoFromReaderContext(context.Background(), "page", bytes.NewReader(image), "", true, opts)
+	if !errors.Is(err, ErrMediaTooLarge) {
+		t.Errorf("Expected ErrMediaTooLarge, got %v", err)
+	}
+}
+
+func TestCreateMultiPhotoPostRollsBackOnFailure(t *testing.T) {
+	var mu sync.Mutex
+	uploads := 0
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case req.Method == "POST" && strings.HasSuffix(req.URL.Path, "/photos"):
+			if req.FormValue("published") != "false" {
+				t.Errorf("Expected photos to be uploaded unpublished")
+			}
+			uploads++
+			fmt.Fprintf(w, `{"id":"photo_%d"}`, uploads)
+		case req.Method == "POST" && strings.HasSuffix(req.URL.Path, "/feed"):
+			if req.URL.Query().Get("attached_media[1]") != `{"media_fbid":"photo_2"}` {
+				t.Errorf("Expected attached_media for photo_2, got %v", req.URL.Query())
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"message":"Invalid parameter","code":100}}`))
+		case req.Method == "DELETE":
+			deleted = append(deleted, strings.TrimPrefix(req.URL.Path, "/v23.0/"))
+			w.Write([]byte(`{"success":true}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test_token")
+	client.BaseURL = server.URL
+
+	photos := []MultiPhotoItem{
+		{Reader: bytes.NewReader(encodePNG(t, 10, 10))},
+		{Reader: bytes.NewReader(encodePNG(t, 20, 20)), Caption: "second"},
+	}
+
+	_, err := client.CreateMultiPhotoPost("page", "Carousel", photos)
+	if err == nil || !strings.Contains(err.Error(), "Invalid parameter") {
+		t.Fatalf("Expected the feed error to be returned, got %v", err)
+	}
+	if len(deleted) != 2 || deleted[0] != "photo_1" || deleted[1] != "photo_2" {
+		t.Errorf("Expected both uploaded photos to be deleted, got %v", deleted)
+	}
+}
+
+func TestCreateMultiPhotoPostRollsBackAfterCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	var uploaded, deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.Method == "POST" && strings.HasSuffix(req.URL.Path, "/photos"):
+			mu.Lock()
+			uploaded = append(uploaded, req.FormValue("url"))
+			fmt.Fprintf(w, `{"id":"photo_%d"}`, len(uploaded))
+			mu.Unlock()
+		case req.Method == "POST" && strings.HasSuffix(req.URL.Path, "/feed"):
+			// The caller goes away while the post is being created
+			cancel()
+			<-req.Context().Done()
+		case req.Method == "DELETE":
+			mu.Lock()
+			deleted = append(deleted, strings.TrimPrefix(req.URL.Path, "/v23.0/"))
+			mu.Unlock()
+			w.Write([]byte(`{"success":true}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test_token")
+	client.BaseURL = server.URL
+
+	photos := []MultiPhotoItem{{URL: "https://example.com/a.png"}, {URL: "https://example.com/b.png"}}
+	_, err := client.CreateMultiPhotoPostContext(ctx, "page", "Carousel", photos)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the cancellation to be returned, got %v", err)
+	}
+	if strings.Contains(err.Error(), "rolling back") {
+		t.Errorf("Expected the rollback to succeed, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(uploaded) != 2 || len(deleted) != 2 || deleted[0] != "photo_1" || deleted[1] != "photo_2" {
+		t.Errorf("Expected both uploaded photos to be deleted, got uploads %v and deletes %v", uploaded, deleted)
+	}
+}
+
+func TestSimpleRouterStreamsPhotoUpload(t *testing.T) {
+	var message string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		message = req.FormValue("message")
+		w.Write([]byte(`{"id":"photo_1","post_id":"page_post_1"}`))
+	}))
+	defer server.Close()
+
+	router := NewSimpleRouter("test_token")
+	router.defaultClient.BaseURL = server.URL
+
+	upload := func(image []byte) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		writer.WriteField("message", "Hello")
+		part, _ := writer.CreateFormFile("file", "photo.png")
+		part.Write(image)
+		writer.Close()
+
+		req := httptest.NewRequest("POST", "/api/pages/page_1/photos", &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := upload(encodePNG(t, 10, 10)); rec.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if message != "Hello" {
+		t.Errorf("Expected message to be forwarded, got %q", message)
+	}
+
+	oversized := append(encodePNG(t, 10, 10), make([]byte, DefaultPhotoLimits.MaxBytes)...)
+	if rec := upload(oversized); rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected 413 for an oversized photo, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
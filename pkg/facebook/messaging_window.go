@@ -0,0 +1,264 @@
+package facebook
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	// StandardMessagingWindow is how long after a user's last message a page may reply freely
+	StandardMessagingWindow = 24 * time.Hour
+	// HumanAgentWindow is how long the HUMAN_AGENT tag extends the window
+	HumanAgentWindow = 7 * 24 * time.Hour
+)
+
+// ErrMessagingWindowClosed is matched by errors.Is for every MessagingPolicyError
+var ErrMessagingWindowClosed = errors.New("messaging window closed")
+
+// MessagingPolicyError reports a send refused because it would violate the
+// Messenger messaging window policy
+type MessagingPolicyError struct {
+	PageID          string    `json:"page_id"`
+	PSID            string    `json:"psid"`
+	LastUserMessage time.Time `json:"last_user_message"`
+	Reason          string    `json:"reason"`
+}
+
+func (e *MessagingPolicyError) Error() string {
+	return fmt.Sprintf("messaging policy: %s", e.Reason)
+}
+
+// Unwrap allows errors.Is(err, ErrMessagingWindowClosed)
+func (e *MessagingPolicyError) Unwrap() error {
+	return ErrMessagingWindowClosed
+}
+
+// MessagingWindowTracker tracks the last user-initiated message per PSID so
+// sends outside the standard messaging window can be refused before they
+// reach the Send API
+type MessagingWindowTracker struct {
+	// RefreshInterval is the minimum time between conversation refreshes of a page
+	RefreshInterval time.Duration
+	// ConversationLimit is the number of recent conversations read on refresh
+	ConversationLimit int
+	// Metrics counts lookups answered from the tracker (hits) and those
+	// that needed a refresh (misses) when set
+	Metrics *MetricsRegistry
+	// Logf reports refresh failures; defaults to log.Printf
+	Logf func(format string, args ...interface{})
+
+	mu        sync.RWMutex
+	last      map[string]time.Time
+	refreshed map[string]time.Time
+	pageIDs   map[string]string
+	now       func() time.Time
+}
+
+// NewMessagingWindowTracker creates an empty tracker
+func NewMessagingWindowTracker() *MessagingWindowTracker {
+	return &MessagingWindowTracker{
+		RefreshInterval:   time.Minute,
+		ConversationLimit: 50,
+		Logf:              log.Printf,
+		last:              make(map[string]time.Time),
+		refreshed:         make(map[string]time.Time),
+		pageIDs:           make(map[string]string),
+		now:               time.Now,
+	}
+}
+
+func windowKey(pageID, psid string) string {
+	return pageID + "/" + psid
+}
+
+// RecordUserMessage records a message sent by a user to a page, e.g. from a
+// Messenger webhook. Older timestamps than the one on record are ignored.
+func (t *MessagingWindowTracker) RecordUserMessage(pageID, psid string, at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := windowKey(pageID, psid)
+	if at.After(t.last[key]) {
+		t.last[key] = at
+	}
+}
+
+// LastUserMessage returns the last user-initiated message time for a PSID
+func (t *MessagingWindowTracker) LastUserMessage(pageID, psid string) (time.Time, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	last, ok := t.last[windowKey(pageID, psid)]
+	return last, ok
+}
+
+// resolvePageID returns the numeric ID of a page referenced as "me", by
+// username or by ID. Webhooks report numeric IDs and the page's own messages
+// carry it in from.id, so the tracker is always keyed on it.
+func (t *MessagingWindowTracker) resolvePageID(client *Client, pageID string) (string, error) {
+	if isNumericID(pageID) {
+		return pageID, nil
+	}
+
+	t.mu.RLock()
+	resolved, ok := t.pageIDs[pageID]
+	t.mu.RUnlock()
+	if ok {
+		return resolved, nil
+	}
+
+	page, err := client.GetPage(pageID, "id")
+	if err != nil {
+		return "", fmt.Errorf("resolving page ID: %w", err)
+	}
+
+	// "me" depends on the token, so only usernames are cached
+	if pageID != "me" {
+		t.mu.Lock()
+		t.pageIDs[pageID] = page.ID
+		t.mu.Unlock()
+	}
+
+	return page.ID, nil
+}
+
+// isNumericID reports whether id is a numeric Graph object ID
+func isNumericID(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Refresh reads recent conversations of a page and records the latest
+// message timestamp of every participant other than the page itself
+func (t *MessagingWindowTracker) Refresh(client *Client, pageID string) error {
+	pageID, err := t.resolvePageID(client, pageID)
+	if err != nil {
+		return fmt.Errorf("refreshing messaging window: %w", err)
+	}
+
+	return t.refresh(client, pageID)
+}
+
+// refresh is Refresh for an already resolved page ID
+func (t *MessagingWindowTracker) refresh(client *Client, pageID string) error {
+	conversations, err := client.GetConversations(pageID, t.ConversationLimit,
+		"id", "updated_time", "messages.limit(25){from,created_time}")
+	if err != nil {
+		return fmt.Errorf("refreshing messaging window: %w", err)
+	}
+
+	for _, conversation := range conversations.Data {
+		if conversation.Messages == nil {
+			continue
+		}
+		for _, message := range conversation.Messages.Data {
+			if message.From.ID == "" || message.From.ID == pageID {
+				continue
+			}
+			t.RecordUserMessage(pageID, message.From.ID, message.CreatedTime.Time)
+		}
+	}
+
+	t.mu.Lock()
+	t.refreshed[pageID] = t.now()
+	t.mu.Unlock()
+
+	return nil
+}
+
+// needsRefresh reports whether the page should be re-read before deciding on psid
+func (t *MessagingWindowTracker) needsRefresh(pageID, psid string) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	now := t.now()
+	if last, ok := t.last[windowKey(pageID, psid)]; ok && now.Sub(last) <= StandardMessagingWindow {
+		return false
+	}
+
+	return now.Sub(t.refreshed[pageID]) >= t.RefreshInterval
+}
+
+// Check applies the messaging window policy to a Send API request.
+// Within 24 hours of the user's last message any message may be sent;
+// afterwards only MESSAGE_TAG messages are allowed, and HUMAN_AGENT only
+// within 7 days. Private replies to comments are not subject to the window.
+func (t *MessagingWindowTracker) Check(pageID string, message *SendMessageRequest) error {
+	psid := message.Recipient.ID
+	if psid == "" {
+		return nil
+	}
+
+	last, ok := t.LastUserMessage(pageID, psid)
+	elapsed := t.now().Sub(last)
+
+	if ok && elapsed <= StandardMessagingWindow {
+		return nil
+	}
+
+	policyErr := &MessagingPolicyError{
+		PageID:          pageID,
+		PSID:            psid,
+		LastUserMessage: last,
+	}
+
+	if message.MessagingType != MessagingTypeMessageTag || message.Tag == "" {
+		if !ok {
+			policyErr.Reason = "no user-initiated message on record; a message tag is required"
+		} else {
+			policyErr.Reason = fmt.Sprintf("24-hour messaging window closed %s ago; a message tag is required",
+				(elapsed - StandardMessagingWindow).Round(time.Minute))
+		}
+		return policyErr
+	}
+
+	if message.Tag == MessageTagHumanAgent && (!ok || elapsed > HumanAgentWindow) {
+		policyErr.Reason = "HUMAN_AGENT tag is only allowed within 7 days of the user's last message"
+		return policyErr
+	}
+
+	return nil
+}
+
+// SendMessageWithinWindow sends a message after checking it against the
+// messaging window policy. Unknown or expired PSIDs trigger a refresh from
+// the page's conversations before the policy is applied; if the refresh
+// fails the policy is applied to what the tracker has on record.
+func (c *Client) SendMessageWithinWindow(pageID string, message *SendMessageRequest, tracker *MessagingWindowTracker) (*SendMessageResponse, error) {
+	if err := message.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid message: %w", err)
+	}
+
+	windowPageID := pageID
+	if psid := message.Recipient.ID; psid != "" {
+		resolved, err := tracker.resolvePageID(c, pageID)
+		if err == nil {
+			windowPageID = resolved
+		}
+
+		refresh := tracker.needsRefresh(windowPageID, psid)
+		tracker.Metrics.recordCacheLookup("messaging_window", !refresh)
+		if refresh && err == nil {
+			err = tracker.refresh(c, windowPageID)
+		}
+		if err != nil {
+			tracker.Logf("messaging window: %v; using recorded messages for page %s", err, pageID)
+		}
+	}
+
+	if err := tracker.Check(windowPageID, message); err != nil {
+		return nil, err
+	}
+
+	return c.SendMessage(pageID, message)
+}
@@ -0,0 +1,143 @@
+package facebook
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newWindowServer serves page 123 as "me" with a conversation holding a
+// user message and a later reply from the page. Conversations fail with a
+// Graph error while failing is set.
+func newWindowServer(t *testing.T, failing *atomic.Bool, sent *atomic.Int32) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		path := strings.TrimPrefix(req.URL.Path, "/"+DefaultAPIVersion+"/")
+		switch {
+		case path == "me":
+			fmt.Fprint(w, `{"id":"123"}`)
+		case path == "123/conversations" && failing.Load():
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"error":{"message":"Service unavailable","type":"OAuthException","code":2}}`)
+		case path == "123/conversations":
+			fmt.Fprint(w, `{"data":[{"id":"t_1","messages":{"data":[
+				{"id":"m_2","created_time":"2024-03-04T11:00:00+0000","from":{"id":"123"}},
+				{"id":"m_1","created_time":"2024-03-04T09:00:00+0000","from":{"id":"psid_1"}}]}}]}`)
+		case path == "me/messages" && req.Method == "POST":
+			sent.Add(1)
+			fmt.Fprint(w, `{"recipient_id":"psid_1","message_id":"m_3"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestMessagingWindowRefreshResolvesPageID(t *testing.T) {
+	var failing atomic.Bool
+	var sent atomic.Int32
+	server := newWindowServer(t, &failing, &sent)
+
+	client := NewClient("page_token")
+	client.BaseURL = server.URL
+
+	tracker := NewMessagingWindowTracker()
+	if err := tracker.Refresh(client, "me"); err != nil {
+		t.Fatalf("Expected refresh to succeed, got %v", err)
+	}
+
+	last, ok := tracker.LastUserMessage("123", "psid_1")
+	if !ok || !last.Equal(time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the user's message under the numeric page ID, got %v %v", last, ok)
+	}
+	if _, ok := tracker.LastUserMessage("123", "123"); ok {
+		t.Error("Expected the page's own reply not to count as a user message")
+	}
+	if _, ok := tracker.LastUserMessage("me", "psid_1"); ok {
+		t.Error("Expected nothing to be recorded under \"me\"")
+	}
+}
+
+func TestMessagingWindowCheck(t *testing.T) {
+	tracker := NewMessagingWindowTracker()
+	lastMessage := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	tracker.RecordUserMessage("123", "psid_1", lastMessage)
+
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		psid    string
+		tag     string
+		allowed bool
+	}{
+		{name: "within 24 hours", elapsed: 23 * time.Hour, psid: "psid_1", allowed: true},
+		{name: "after 24 hours", elapsed: 25 * time.Hour, psid: "psid_1"},
+		{name: "after 24 hours with tag", elapsed: 25 * time.Hour, psid: "psid_1", tag: MessageTagAccountUpdate, allowed: true},
+		{name: "human agent within 7 days", elapsed: 6 * 24 * time.Hour, psid: "psid_1", tag: MessageTagHumanAgent, allowed: true},
+		{name: "human agent after 7 days", elapsed: 8 * 24 * time.Hour, psid: "psid_1", tag: MessageTagHumanAgent},
+		{name: "unknown user", elapsed: time.Hour, psid: "psid_2"},
+		{name: "unknown user with tag", elapsed: time.Hour, psid: "psid_2", tag: MessageTagPostPurchaseUpdate, allowed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker.now = func() time.Time { return lastMessage.Add(tt.elapsed) }
+
+			message := NewTextMessage(tt.psid, "Hello")
+			if tt.tag != "" {
+				message.WithTag(tt.tag)
+			}
+
+			err := tracker.Check("123", message)
+			if tt.allowed && err != nil {
+				t.Errorf("Expected message to be allowed, got %v", err)
+			}
+			if !tt.allowed && !errors.Is(err, ErrMessagingWindowClosed) {
+				t.Errorf("Expected ErrMessagingWindowClosed, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSendMessageWithinWindowFallsBackWhenRefreshFails(t *testing.T) {
+	var failing atomic.Bool
+	var sent atomic.Int32
+	server := newWindowServer(t, &failing, &sent)
+	failing.Store(true)
+
+	client := NewClient("page_token")
+	client.BaseURL = server.URL
+
+	var logged []string
+	tracker := NewMessagingWindowTracker()
+	tracker.Logf = func(format string, args ...interface{}) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	}
+	now := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+
+	// Nothing on record and no refresh possible: refused by policy, not failed
+	_, err := client.SendMessageWithinWindow("me", NewTextMessage("psid_1", "Hello"), tracker)
+	var policyErr *MessagingPolicyError
+	if !errors.As(err, &policyErr) {
+		t.Fatalf("Expected a policy error, got %v", err)
+	}
+	if len(logged) != 1 {
+		t.Errorf("Expected the refresh failure to be logged, got %v", logged)
+	}
+
+	// A message recorded by a webhook keeps the window open despite the outage
+	now = now.Add(2 * time.Minute)
+	tracker.RecordUserMessage("123", "psid_1", now.Add(-time.Hour))
+	if _, err := client.SendMessageWithinWindow("me", NewTextMessage("psid_1", "Hello"), tracker); err != nil {
+		t.Fatalf("Expected send within the recorded window to succeed, got %v", err)
+	}
+	if sent.Load() != 1 {
+		t.Errorf("Expected one message to be sent, got %d", sent.Load())
+	}
+}
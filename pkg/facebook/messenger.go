@@ -0,0 +1,362 @@
+package facebook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// Messaging types accepted by the Send API
+const (
+	MessagingTypeResponse   = "RESPONSE"
+	MessagingTypeUpdate     = "UPDATE"
+	MessagingTypeMessageTag = "MESSAGE_TAG"
+)
+
+// Message tags allowing messages outside the standard messaging window
+const (
+	MessageTagConfirmedEventUpdate = "CONFIRMED_EVENT_UPDATE"
+	MessageTagPostPurchaseUpdate   = "POST_PURCHASE_UPDATE"
+	MessageTagAccountUpdate        = "ACCOUNT_UPDATE"
+	MessageTagHumanAgent           = "HUMAN_AGENT"
+)
+
+// Send API limits
+const (
+	MaxMessageTextLength = 2000
+	MaxQuickReplies      = 13
+)
+
+// Conversation represents a page inbox conversation
+type Conversation struct {
+	ID           string            `json:"id"`
+	Link         string            `json:"link,omitempty"`
+	Snippet      string            `json:"snippet,omitempty"`
+	UpdatedTime  FacebookTime      `json:"updated_time,omitempty"`
+	MessageCount int               `json:"message_count,omitempty"`
+	UnreadCount  int               `json:"unread_count,omitempty"`
+	CanReply     bool              `json:"can_reply,omitempty"`
+	Participants ParticipantList   `json:"participants,omitempty"`
+	Messages     *MessagesResponse `json:"messages,omitempty"`
+}
+
+// Participant represents a conversation participant
+type Participant struct {
+	ID    string `json:"id"`
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+// ParticipantList represents a list of participants
+type ParticipantList struct {
+	Data []Participant `json:"data"`
+}
+
+// ConversationsResponse represents a paginated list of conversations
+type ConversationsResponse struct {
+	Data   []Conversation `json:"data"`
+	Paging PagingData     `json:"paging,omitempty"`
+}
+
+// Message represents a message in a conversation
+type Message struct {
+	ID          string                 `json:"id"`
+	CreatedTime FacebookTime           `json:"created_time"`
+	From        Participant            `json:"from"`
+	To          ParticipantList        `json:"to,omitempty"`
+	Message     string                 `json:"message,omitempty"`
+	Sticker     string                 `json:"sticker,omitempty"`
+	Attachments *MessageAttachmentList `json:"attachments,omitempty"`
+}
+
+// MessageAttachmentList represents the attachments of a message
+type MessageAttachmentList struct {
+	Data []MessageAttachment `json:"data"`
+}
+
+// MessageAttachment represents a file attached to a message
+type MessageAttachment struct {
+	ID        string            `json:"id"`
+	MimeType  string            `json:"mime_type,omitempty"`
+	Name      string            `json:"name,omitempty"`
+	Size      int               `json:"size,omitempty"`
+	FileURL   string            `json:"file_url,omitempty"`
+	ImageData *MessageImageData `json:"image_data,omitempty"`
+}
+
+// MessageImageData contains image details of a message attachment
+type MessageImageData struct {
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	URL        string `json:"url"`
+	PreviewURL string `json:"preview_url,omitempty"`
+}
+
+// MessagesResponse represents a paginated list of messages
+type MessagesResponse struct {
+	Data   []Message  `json:"data"`
+	Paging PagingData `json:"paging,omitempty"`
+}
+
+// SendMessageRequest represents a Send API request
+type SendMessageRequest struct {
+	Recipient     MessageRecipient `json:"recipient"`
+	MessagingType string           `json:"messaging_type,omitempty"`
+	Tag           string           `json:"tag,omitempty"`
+	Message       *OutgoingMessage `json:"message,omitempty"`
+	SenderAction  string           `json:"sender_action,omitempty"`
+}
+
+// MessageRecipient identifies who receives a message.
+// Use ID for a page-scoped ID (PSID), or CommentID to send a private reply.
+type MessageRecipient struct {
+	ID        string `json:"id,omitempty"`
+	CommentID string `json:"comment_id,omitempty"`
+}
+
+// OutgoingMessage represents the content of a Send API message
+type OutgoingMessage struct {
+	Text         string              `json:"text,omitempty"`
+	Attachment   *OutgoingAttachment `json:"attachment,omitempty"`
+	QuickReplies []QuickReply        `json:"quick_replies,omitempty"`
+	Metadata     string              `json:"metadata,omitempty"`
+}
+
+// QuickReply represents a quick reply button
+type QuickReply struct {
+	ContentType string `json:"content_type"`
+	Title       string `json:"title,omitempty"`
+	Payload     string `json:"payload,omitempty"`
+	ImageURL    string `json:"image_url,omitempty"`
+}
+
+// OutgoingAttachment represents an attachment or template in a message.
+// Payload is an *AttachmentPayload for media or a *TemplatePayload for templates.
+type OutgoingAttachment struct {
+	Type    string      `json:"type"`
+	Payload interface{} `json:"payload"`
+}
+
+// AttachmentPayload references media by URL or by a saved attachment ID
+type AttachmentPayload struct {
+	URL          string `json:"url,omitempty"`
+	IsReusable   bool   `json:"is_reusable,omitempty"`
+	AttachmentID string `json:"attachment_id,omitempty"`
+}
+
+// TemplatePayload represents a structured message template
+type TemplatePayload struct {
+	TemplateType string            `json:"template_type"`
+	Text         string            `json:"text,omitempty"`
+	Elements     []TemplateElement `json:"elements,omitempty"`
+	Buttons      []TemplateButton  `json:"buttons,omitempty"`
+}
+
+// TemplateElement represents an element of a generic template
+type TemplateElement struct {
+	Title         string           `json:"title"`
+	Subtitle      string           `json:"subtitle,omitempty"`
+	ImageURL      string           `json:"image_url,omitempty"`
+	DefaultAction *TemplateButton  `json:"default_action,omitempty"`
+	Buttons       []TemplateButton `json:"buttons,omitempty"`
+}
+
+// TemplateButton represents a button in a template
+type TemplateButton struct {
+	Type    string `json:"type"`
+	Title   string `json:"title,omitempty"`
+	URL     string `json:"url,omitempty"`
+	Payload string `json:"payload,omitempty"`
+}
+
+// SendMessageResponse represents the response of the Send API
+type SendMessageResponse struct {
+	RecipientID string `json:"recipient_id"`
+	MessageID   string `json:"message_id"`
+}
+
+// NewTextMessage creates a RESPONSE text message to a PSID
+func NewTextMessage(psid, text string) *SendMessageRequest {
+	return &SendMessageRequest{
+		Recipient:     MessageRecipient{ID: psid},
+		MessagingType: MessagingTypeResponse,
+		Message:       &OutgoingMessage{Text: text},
+	}
+}
+
+// NewAttachmentMessage creates a RESPONSE message with an image, video, audio or file attachment
+func NewAttachmentMessage(psid, attachmentType, attachmentURL string) *SendMessageRequest {
+	return &SendMessageRequest{
+		Recipient:     MessageRecipient{ID: psid},
+		MessagingType: MessagingTypeResponse,
+		Message: &OutgoingMessage{
+			Attachment: &OutgoingAttachment{
+				Type:    attachmentType,
+				Payload: &AttachmentPayload{URL: attachmentURL},
+			},
+		},
+	}
+}
+
+// NewTemplateMessage creates a RESPONSE message with a template attachment
+func NewTemplateMessage(psid string, template *TemplatePayload) *SendMessageRequest {
+	return &SendMessageRequest{
+		Recipient:     MessageRecipient{ID: psid},
+		MessagingType: MessagingTypeResponse,
+		Message: &OutgoingMessage{
+			Attachment: &OutgoingAttachment{
+				Type:    "template",
+				Payload: template,
+			},
+		},
+	}
+}
+
+// WithTag marks the message as a MESSAGE_TAG message with the given tag
+func (m *SendMessageRequest) WithTag(tag string) *SendMessageRequest {
+	m.MessagingType = MessagingTypeMessageTag
+	m.Tag = tag
+	return m
+}
+
+// Validate checks a Send API request before it is sent
+func (m *SendMessageRequest) Validate() error {
+	if m.Recipient.ID == "" && m.Recipient.CommentID == "" {
+		return fmt.Errorf("recipient id or comment_id is required")
+	}
+
+	// Sender actions (typing indicators, mark_seen) carry no message
+	if m.Message == nil {
+		if m.SenderAction == "" {
+			return fmt.Errorf("message or sender_action is required")
+		}
+		return nil
+	}
+
+	if m.SenderAction != "" {
+		return fmt.Errorf("message and sender_action cannot be combined")
+	}
+
+	switch m.MessagingType {
+	case MessagingTypeResponse, MessagingTypeUpdate:
+		if m.Tag != "" {
+			return fmt.Errorf("tag is only allowed with messaging_type %s", MessagingTypeMessageTag)
+		}
+	case MessagingTypeMessageTag:
+		switch m.Tag {
+		case MessageTagConfirmedEventUpdate, MessageTagPostPurchaseUpdate,
+			MessageTagAccountUpdate, MessageTagHumanAgent:
+		case "":
+			return fmt.Errorf("tag is required with messaging_type %s", MessagingTypeMessageTag)
+		default:
+			return fmt.Errorf("unsupported message tag %q", m.Tag)
+		}
+	default:
+		return fmt.Errorf("invalid messaging_type %q", m.MessagingType)
+	}
+
+	if m.Message.Text == "" && m.Message.Attachment == nil {
+		return fmt.Errorf("message text or attachment is required")
+	}
+
+	if m.Message.Text != "" && m.Message.Attachment != nil {
+		return fmt.Errorf("message text and attachment cannot be combined")
+	}
+
+	if utf8.RuneCountInString(m.Message.Text) > MaxMessageTextLength {
+		return fmt.Errorf("message text exceeds %d characters", MaxMessageTextLength)
+	}
+
+	if len(m.Message.QuickReplies) > MaxQuickReplies {
+		return fmt.Errorf("at most %d quick replies are allowed", MaxQuickReplies)
+	}
+
+	return nil
+}
+
+// GetConversations retrieves inbox conversations of a Facebook page
+func (c *Client) GetConversations(pageID string, limit int, fields ...string) (*ConversationsResponse, error) {
+	params := url.Values{}
+
+	if limit > 0 {
+		params.Set("limit", fmt.Sprintf("%d", limit))
+	}
+
+	if len(fields) == 0 {
+		fields = []string{
+			"id", "link", "snippet", "updated_time",
+			"message_count", "unread_count", "can_reply", "participants",
+		}
+	}
+	params.Set("fields", strings.Join(fields, ","))
+
+	endpoint := fmt.Sprintf("%s/conversations", pageID)
+	resp, err := c.makeRequest("GET", endpoint, params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting conversations: %w", err)
+	}
+
+	var conversationsResp ConversationsResponse
+	if err := c.handleResponse(resp, &conversationsResp); err != nil {
+		return nil, err
+	}
+
+	return &conversationsResp, nil
+}
+
+// GetConversationMessages retrieves messages of a conversation, newest first
+func (c *Client) GetConversationMessages(conversationID string, limit int, fields ...string) (*MessagesResponse, error) {
+	params := url.Values{}
+
+	if limit > 0 {
+		params.Set("limit", fmt.Sprintf("%d", limit))
+	}
+
+	if len(fields) == 0 {
+		fields = []string{
+			"id", "created_time", "from", "to", "message", "sticker", "attachments",
+		}
+	}
+	params.Set("fields", strings.Join(fields, ","))
+
+	endpoint := fmt.Sprintf("%s/messages", conversationID)
+	resp, err := c.makeRequest("GET", endpoint, params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting conversation messages: %w", err)
+	}
+
+	var messagesResp MessagesResponse
+	if err := c.handleResponse(resp, &messagesResp); err != nil {
+		return nil, err
+	}
+
+	return &messagesResp, nil
+}
+
+// SendMessage sends a message through the Send API. Requires a page access token.
+func (c *Client) SendMessage(pageID string, message *SendMessageRequest) (*SendMessageResponse, error) {
+	if err := message.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid message: %w", err)
+	}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling message: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/messages", pageID)
+	resp, err := c.makeRequest("POST", endpoint, nil, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("sending message: %w", err)
+	}
+
+	var sendResp SendMessageResponse
+	if err := c.handleResponse(resp, &sendResp); err != nil {
+		return nil, err
+	}
+
+	return &sendResp, nil
+}
@@ -0,0 +1,245 @@
+package facebook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSendMessageRequestValidate(t *testing.T) {
+	text := &OutgoingMessage{Text: "Hello"}
+
+	tests := []struct {
+		name    string
+		message SendMessageRequest
+		wantErr string
+	}{
+		{
+			name:    "text response",
+			message: SendMessageRequest{Recipient: MessageRecipient{ID: "psid"}, MessagingType: MessagingTypeResponse, Message: text},
+		},
+		{
+			name:    "private reply",
+			message: SendMessageRequest{Recipient: MessageRecipient{CommentID: "comment_1"}, MessagingType: MessagingTypeResponse, Message: text},
+		},
+		{
+			name:    "sender action",
+			message: SendMessageRequest{Recipient: MessageRecipient{ID: "psid"}, SenderAction: "typing_on"},
+		},
+		{
+			name:    "tagged update",
+			message: SendMessageRequest{Recipient: MessageRecipient{ID: "psid"}, MessagingType: MessagingTypeMessageTag, Tag: MessageTagHumanAgent, Message: text},
+		},
+		{
+			name:    "missing recipient",
+			message: SendMessageRequest{MessagingType: MessagingTypeResponse, Message: text},
+			wantErr: "recipient id or comment_id is required",
+		},
+		{
+			name:    "missing message",
+			message: SendMessageRequest{Recipient: MessageRecipient{ID: "psid"}, MessagingType: MessagingTypeResponse},
+			wantErr: "message or sender_action is required",
+		},
+		{
+			name:    "message with sender action",
+			message: SendMessageRequest{Recipient: MessageRecipient{ID: "psid"}, MessagingType: MessagingTypeResponse, Message: text, SenderAction: "mark_seen"},
+			wantErr: "message and sender_action cannot be combined",
+		},
+		{
+			name:    "tag without MESSAGE_TAG",
+			message: SendMessageRequest{Recipient: MessageRecipient{ID: "psid"}, MessagingType: MessagingTypeUpdate, Tag: MessageTagAccountUpdate, Message: text},
+			wantErr: "tag is only allowed with messaging_type MESSAGE_TAG",
+		},
+		{
+			name:    "MESSAGE_TAG without tag",
+			message: SendMessageRequest{Recipient: MessageRecipient{ID: "psid"}, MessagingType: MessagingTypeMessageTag, Message: text},
+			wantErr: "tag is required with messaging_type MESSAGE_TAG",
+		},
+		{
+			name:    "unsupported tag",
+			message: SendMessageRequest{Recipient: MessageRecipient{ID: "psid"}, MessagingType: MessagingTypeMessageTag, Tag: "ISSUE_RESOLUTION", Message: text},
+			wantErr: `unsupported message tag "ISSUE_RESOLUTION"`,
+		},
+		{
+			name:    "missing messaging type",
+			message: SendMessageRequest{Recipient: MessageRecipient{ID: "psid"}, Message: text},
+			wantErr: `invalid messaging_type ""`,
+		},
+		{
+			name:    "empty message",
+			message: SendMessageRequest{Recipient: MessageRecipient{ID: "psid"}, MessagingType: MessagingTypeResponse, Message: &OutgoingMessage{}},
+			wantErr: "message text or attachment is required",
+		},
+		{
+			name: "text with attachment",
+			message: SendMessageRequest{Recipient: MessageRecipient{ID: "psid"}, MessagingType: MessagingTypeResponse, Message: &OutgoingMessage{
+				Text:       "Hello",
+				Attachment: &OutgoingAttachment{Type: "image", Payload: &AttachmentPayload{URL: "https://example.com/a.jpg"}},
+			}},
+			wantErr: "message text and attachment cannot be combined",
+		},
+		{
+			name:    "text too long",
+			message: SendMessageRequest{Recipient: MessageRecipient{ID: "psid"}, MessagingType: MessagingTypeResponse, Message: &OutgoingMessage{Text: strings.Repeat("é", MaxMessageTextLength+1)}},
+			wantErr: "message text exceeds 2000 characters",
+		},
+		{
+			name: "too many quick replies",
+			message: SendMessageRequest{Recipient: MessageRecipient{ID: "psid"}, MessagingType: MessagingTypeResponse, Message: &OutgoingMessage{
+				Text:         "Pick one",
+				QuickReplies: make([]QuickReply, MaxQuickReplies+1),
+			}},
+			wantErr: "at most 13 quick replies are allowed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.message.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// fakeMessenger serves conversations, messages and the Send API of page_1
+// and records the bodies sent to the Send API
+type fakeMessenger struct {
+	mu   sync.Mutex
+	sent []string
+}
+
+func (f *fakeMessenger) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(req.URL.Path, "/"+DefaultAPIVersion+"/")
+
+	switch {
+	case req.Method == "GET" && path == "page_1":
+		fmt.Fprint(w, `{"id":"page_1","name":"Page"}`)
+	case req.Method == "GET" && path == "page_1/conversations":
+		fmt.Fprint(w, `{"data":[{"id":"t_1","participants":{"data":[{"id":"psid_1"},{"id":"page_1"}]}}]}`)
+	case req.Method == "GET" && path == "t_1/messages":
+		fmt.Fprint(w, `{"data":[{"id":"m_1","created_time":"2024-03-04T09:00:00+0000","from":{"id":"psid_1"},"message":"Hi"}]}`)
+	case req.Method == "POST" && path == "page_1/messages":
+		body, _ := io.ReadAll(req.Body)
+		f.sent = append(f.sent, string(body))
+		fmt.Fprint(w, `{"recipient_id":"psid_1","message_id":"m_2"}`)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":{"message":"Unknown path","type":"GraphMethodException","code":100}}`)
+	}
+}
+
+func (f *fakeMessenger) bodies() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.sent...)
+}
+
+func TestSendMessageRequestBody(t *testing.T) {
+	fake := &fakeMessenger{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client := NewClient("page_token")
+	client.BaseURL = server.URL
+
+	result, err := client.SendMessage("page_1", NewTextMessage("psid_1", "Hello").WithTag(MessageTagAccountUpdate))
+	if err != nil {
+		t.Fatalf("Expected send to succeed, got %v", err)
+	}
+	if result.MessageID != "m_2" || result.RecipientID != "psid_1" {
+		t.Errorf("Unexpected send response %+v", result)
+	}
+
+	bodies := fake.bodies()
+	if len(bodies) != 1 {
+		t.Fatalf("Expected one Send API request, got %d", len(bodies))
+	}
+	want := `{"recipient":{"id":"psid_1"},"messaging_type":"MESSAGE_TAG","tag":"ACCOUNT_UPDATE","message":{"text":"Hello"}}`
+	if bodies[0] != want {
+		t.Errorf("Expected body %s, got %s", want, bodies[0])
+	}
+
+	// Invalid messages never reach the Send API
+	if _, err := client.SendMessage("page_1", &SendMessageRequest{Recipient: MessageRecipient{ID: "psid_1"}}); err == nil {
+		t.Error("Expected an invalid message to be rejected")
+	}
+	if len(fake.bodies()) != 1 {
+		t.Error("Expected the invalid message not to be sent")
+	}
+}
+
+func TestMessengerRoutes(t *testing.T) {
+	fake := &fakeMessenger{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	router := NewRouter("page_token")
+	router.defaultClient.BaseURL = server.URL
+
+	simple := NewSimpleRouter("page_token")
+	simple.defaultClient.BaseURL = server.URL
+
+	trackers := map[string]*MessagingWindowTracker{"Router": router.MessagingWindow(), "SimpleRouter": simple.MessagingWindow()}
+	handlers := map[string]http.Handler{"Router": router.SetupRoutes(), "SimpleRouter": simple}
+	for name, handler := range handlers {
+		send := func(method, target, body string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(method, target, strings.NewReader(body))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			return rec
+		}
+
+		rec := send("GET", "/api/pages/page_1/conversations?limit=5&fields=id,participants", "")
+		var conversations ConversationsResponse
+		if rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), &conversations) != nil || len(conversations.Data) != 1 {
+			t.Errorf("%s: unexpected conversations response %d: %s", name, rec.Code, rec.Body.String())
+		}
+
+		rec = send("GET", "/api/conversations/t_1/messages", "")
+		var messages MessagesResponse
+		if rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), &messages) != nil || len(messages.Data) != 1 || messages.Data[0].From.ID != "psid_1" {
+			t.Errorf("%s: unexpected messages response %d: %s", name, rec.Code, rec.Body.String())
+		}
+
+		if rec := send("POST", "/api/pages/page_1/messages", `{"recipient":{"id":"psid_1"}}`); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400 for an invalid message, got %d", name, rec.Code)
+		}
+		if rec := send("POST", "/api/pages/page_1/messages", `{`); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400 for a malformed body, got %d", name, rec.Code)
+		}
+
+		// The fake inbox only has an old message, so the window is closed
+		rec = send("POST", "/api/pages/page_1/messages", `{"recipient":{"id":"psid_1"},"messaging_type":"RESPONSE","message":{"text":"Hi"}}`)
+		if rec.Code != http.StatusForbidden || !strings.Contains(rec.Body.String(), `"policy"`) {
+			t.Errorf("%s: expected 403 outside the messaging window, got %d: %s", name, rec.Code, rec.Body.String())
+		}
+
+		trackers[name].RecordUserMessage("page_1", "psid_1", time.Now())
+		rec = send("POST", "/api/pages/page_1/messages", `{"recipient":{"id":"psid_1"},"messaging_type":"RESPONSE","message":{"text":"Hi"}}`)
+		var result SendMessageResponse
+		if rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), &result) != nil || result.MessageID != "m_2" {
+			t.Errorf("%s: expected message to be sent, got %d: %s", name, rec.Code, rec.Body.String())
+		}
+	}
+
+	if sent := fake.bodies(); len(sent) != 2 {
+		t.Errorf("Expected one message sent per router, got %d", len(sent))
+	}
+}
@@ -0,0 +1,303 @@
+package facebook
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MetricShape describes the JSON shape of an insight value
+type MetricShape string
+
+// Insight value shapes
+const (
+	// MetricShapeNumber values are a single number
+	MetricShapeNumber MetricShape = "number"
+	// MetricShapeBreakdown values are an object of numbers keyed by
+	// dimension, e.g. reaction type or country
+	MetricShapeBreakdown MetricShape = "breakdown"
+)
+
+// MetricObject is the type of object a metric is reported for
+type MetricObject string
+
+// Objects with insights
+const (
+	MetricObjectPage  MetricObject = "page"
+	MetricObjectPost  MetricObject = "post"
+	MetricObjectVideo MetricObject = "video"
+)
+
+// MetricDefinition describes an insights metric
+type MetricDefinition struct {
+	Name    string       `json:"name"`
+	Object  MetricObject `json:"object"`
+	Shape   MetricShape  `json:"shape"`
+	Periods []string     `json:"periods"`
+	// IntroducedIn is the first API version with the metric; empty if it
+	// predates every supported version
+	IntroducedIn string `json:"introduced_in,omitempty"`
+	// DeprecatedIn is the first API version without the metric
+	DeprecatedIn string `json:"deprecated_in,omitempty"`
+	// ReplacedBy names the metric to use instead once deprecated
+	ReplacedBy  string `json:"replaced_by,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// AvailableIn reports whether the metric exists in the given API version
+func (d MetricDefinition) AvailableIn(apiVersion string) bool {
+	if d.IntroducedIn != "" && compareAPIVersions(apiVersion, d.IntroducedIn) < 0 {
+		return false
+	}
+	if d.DeprecatedIn != "" && compareAPIVersions(apiVersion, d.DeprecatedIn) >= 0 {
+		return false
+	}
+	return true
+}
+
+// SupportsPeriod reports whether the metric can be requested with the period
+func (d MetricDefinition) SupportsPeriod(period string) bool {
+	for _, supported := range d.Periods {
+		if supported == period {
+			return true
+		}
+	}
+	return false
+}
+
+// Periods accepted by groups of metrics
+var (
+	periodsDaily         = []string{PeriodDay}
+	periodsLifetime      = []string{PeriodLifetime}
+	periodsLifetimeDaily = []string{PeriodDay, PeriodLifetime}
+	periodsRolling       = []string{PeriodDay, PeriodWeek, PeriodDays28, PeriodTotalOverRange}
+)
+
+// metricCatalog lists the insights metrics known to the client
+var metricCatalog = []MetricDefinition{
+	// Page impressions and engagement
+	{Name: "page_impressions", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsRolling, Description: "Times any content from or about the page entered a screen"},
+	{Name: "page_impressions_unique", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsRolling, Description: "People who had any content from or about the page enter their screen"},
+	{Name: "page_impressions_paid", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsRolling, Description: "Impressions of paid content"},
+	{Name: "page_impressions_paid_unique", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsRolling, Description: "People reached by paid content"},
+	{Name: "page_impressions_organic", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsRolling, Description: "Impressions of organic content"},
+	{Name: "page_impressions_organic_unique", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsRolling, Description: "People reached by organic content"},
+	{Name: "page_impressions_viral", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsRolling, Description: "Impressions through stories shared by other people"},
+	{Name: "page_impressions_viral_unique", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsRolling, Description: "People reached through stories shared by other people"},
+	{Name: "page_impressions_by_age_gender_unique", Object: MetricObjectPage, Shape: MetricShapeBreakdown, Periods: periodsRolling, DeprecatedIn: "v18.0", Description: "People reached by age and gender"},
+	{Name: "page_posts_impressions", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsRolling, Description: "Times the page's posts entered a screen"},
+	{Name: "page_posts_impressions_unique", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsRolling, Description: "People who had the page's posts enter their screen"},
+	{Name: "page_posts_impressions_paid", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsRolling, Description: "Impressions of the page's posts through paid distribution"},
+	{Name: "page_posts_impressions_paid_unique", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsRolling, Description: "People reached by the page's posts through paid distribution"},
+	{Name: "page_posts_impressions_organic", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsRolling, Description: "Organic impressions of the page's posts"},
+	{Name: "page_posts_impressions_organic_unique", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsRolling, Description: "People reached organically by the page's posts"},
+	{Name: "page_posts_impressions_viral", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsRolling, Description: "Viral impressions of the page's posts"},
+	{Name: "page_posts_impressions_viral_unique", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsRolling, Description: "People reached virally by the page's posts"},
+	{Name: "page_post_engagements", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsRolling, Description: "Reactions, comments, shares and clicks on the page's posts"},
+	{Name: "page_engaged_users", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsRolling, DeprecatedIn: "v18.0", ReplacedBy: "page_post_engagements", Description: "People who engaged with the page"},
+	{Name: "page_consumptions", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsRolling, DeprecatedIn: "v18.0", ReplacedBy: "page_post_engagements", Description: "Clicks on any of the page's content"},
+	{Name: "page_consumptions_unique", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsRolling, DeprecatedIn: "v18.0", ReplacedBy: "page_post_engagements", Description: "People who clicked any of the page's content"},
+	{Name: "page_total_actions", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsRolling, Description: "Clicks on the page's contact info and call-to-action button"},
+	{Name: "page_actions_post_reactions_total", Object: MetricObjectPage, Shape: MetricShapeBreakdown, Periods: periodsRolling, Description: "Reactions on the page's posts by type"},
+	{Name: "page_positive_feedback_by_type", Object: MetricObjectPage, Shape: MetricShapeBreakdown, Periods: periodsRolling, DeprecatedIn: "v18.0", ReplacedBy: "page_actions_post_reactions_total", Description: "Positive actions by type"},
+	{Name: "page_positive_feedback_by_type_unique", Object: MetricObjectPage, Shape: MetricShapeBreakdown, Periods: periodsRolling, DeprecatedIn: "v18.0", ReplacedBy: "page_actions_post_reactions_total", Description: "People who took positive actions, by type"},
+	{Name: "page_negative_feedback", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsRolling, DeprecatedIn: "v18.0", Description: "Negative actions such as hiding a post"},
+	{Name: "page_negative_feedback_unique", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsRolling, DeprecatedIn: "v18.0", Description: "People who took negative actions"},
+	{Name: "page_negative_feedback_by_type", Object: MetricObjectPage, Shape: MetricShapeBreakdown, Periods: periodsRolling, DeprecatedIn: "v18.0", Description: "Negative actions by type"},
+
+	// Page audience
+	{Name: "page_fans", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsLifetimeDaily, DeprecatedIn: "v18.0", ReplacedBy: "page_follows", Description: "People who like the page"},
+	{Name: "page_fan_adds", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsRolling, DeprecatedIn: "v18.0", ReplacedBy: "page_daily_follows_unique", Description: "New likes of the page"},
+	{Name: "page_fan_adds_unique", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsRolling, DeprecatedIn: "v18.0", ReplacedBy: "page_daily_follows_unique", Description: "People who liked the page"},
+	{Name: "page_fan_removes", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsDaily, DeprecatedIn: "v18.0", ReplacedBy: "page_daily_unfollows_unique", Description: "Unlikes of the page"},
+	{Name: "page_fan_removes_unique", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsDaily, DeprecatedIn: "v18.0", ReplacedBy: "page_daily_unfollows_unique", Description: "People who unliked the page"},
+	{Name: "page_fan_adds_by_paid_non_paid_unique", Object: MetricObjectPage, Shape: MetricShapeBreakdown, Periods: periodsRolling, DeprecatedIn: "v18.0", Description: "New likes split by paid and unpaid"},
+	{Name: "page_fan_adds_by_likes_source", Object: MetricObjectPage, Shape: MetricShapeBreakdown, Periods: periodsRolling, DeprecatedIn: "v18.0", Description: "New likes by where they happened"},
+	{Name: "page_fan_adds_by_unlikes_source", Object: MetricObjectPage, Shape: MetricShapeBreakdown, Periods: periodsRolling, DeprecatedIn: "v18.0", Description: "Unlikes by where they happened"},
+	{Name: "page_fans_country", Object: MetricObjectPage, Shape: MetricShapeBreakdown, Periods: periodsLifetimeDaily, DeprecatedIn: "v18.0", Description: "People who like the page by country"},
+	{Name: "page_fans_city", Object: MetricObjectPage, Shape: MetricShapeBreakdown, Periods: periodsLifetimeDaily, DeprecatedIn: "v18.0", Description: "People who like the page by city"},
+	{Name: "page_fans_locale", Object: MetricObjectPage, Shape: MetricShapeBreakdown, Periods: periodsLifetimeDaily, DeprecatedIn: "v18.0", Description: "People who like the page by language"},
+	{Name: "page_fans_gender_age", Object: MetricObjectPage, Shape: MetricShapeBreakdown, Periods: periodsLifetimeDaily, DeprecatedIn: "v18.0", Description: "People who like the page by age and gender"},
+	{Name: "page_follows", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsDaily, Description: "Followers of the page"},
+	{Name: "page_daily_follows", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsDaily, Description: "New followers of the page"},
+	{Name: "page_daily_follows_unique", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsDaily, Description: "People who started following the page"},
+	{Name: "page_daily_unfollows_unique", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsDaily, Description: "People who stopped following the page"},
+
+	// Page views and video
+	{Name: "page_views_total", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsRolling, Description: "Times the page's profile was viewed"},
+	{Name: "page_views_logged_in_total", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsRolling, DeprecatedIn: "v18.0", ReplacedBy: "page_views_total", Description: "Profile views by logged-in people"},
+	{Name: "page_views_logged_in_unique", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsRolling, DeprecatedIn: "v18.0", ReplacedBy: "page_views_total", Description: "Logged-in people who viewed the profile"},
+	{Name: "page_views_external_referrals", Object: MetricObjectPage, Shape: MetricShapeBreakdown, Periods: periodsRolling, DeprecatedIn: "v18.0", Description: "Profile views by referring domain"},
+	{Name: "page_video_views", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsRolling, Description: "Times the page's videos played for at least 3 seconds"},
+	{Name: "page_video_views_unique", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsRolling, Description: "People who viewed the page's videos for at least 3 seconds"},
+	{Name: "page_video_views_by_paid_non_paid", Object: MetricObjectPage, Shape: MetricShapeBreakdown, Periods: periodsRolling, Description: "Video views split by paid and unpaid"},
+	{Name: "page_video_view_time", Object: MetricObjectPage, Shape: MetricShapeNumber, Periods: periodsRolling, Description: "Total time the page's videos were viewed, in milliseconds"},
+
+	// Post metrics
+	{Name: "post_impressions", Object: MetricObjectPost, Shape: MetricShapeNumber, Periods: periodsLifetime, Description: "Times the post entered a screen"},
+	{Name: "post_impressions_unique", Object: MetricObjectPost, Shape: MetricShapeNumber, Periods: periodsLifetime, Description: "People who had the post enter their screen"},
+	{Name: "post_impressions_paid", Object: MetricObjectPost, Shape: MetricShapeNumber, Periods: periodsLifetime, Description: "Paid impressions of the post"},
+	{Name: "post_impressions_paid_unique", Object: MetricObjectPost, Shape: MetricShapeNumber, Periods: periodsLifetime, Description: "People reached by the post through paid distribution"},
+	{Name: "post_impressions_organic", Object: MetricObjectPost, Shape: MetricShapeNumber, Periods: periodsLifetime, Description: "Organic impressions of the post"},
+	{Name: "post_impressions_organic_unique", Object: MetricObjectPost, Shape: MetricShapeNumber, Periods: periodsLifetime, Description: "People reached organically by the post"},
+	{Name: "post_impressions_viral", Object: MetricObjectPost, Shape: MetricShapeNumber, Periods: periodsLifetime, Description: "Viral impressions of the post"},
+	{Name: "post_impressions_viral_unique", Object: MetricObjectPost, Shape: MetricShapeNumber, Periods: periodsLifetime, Description: "People reached virally by the post"},
+	{Name: "post_engaged_users", Object: MetricObjectPost, Shape: MetricShapeNumber, Periods: periodsLifetime, DeprecatedIn: "v18.0", ReplacedBy: "post_clicks", Description: "People who clicked anywhere in the post"},
+	{Name: "post_clicks", Object: MetricObjectPost, Shape: MetricShapeNumber, Periods: periodsLifetime, Description: "Clicks anywhere in the post"},
+	{Name: "post_clicks_by_type", Object: MetricObjectPost, Shape: MetricShapeBreakdown, Periods: periodsLifetime, Description: "Clicks on the post by type"},
+	{Name: "post_reactions_by_type_total", Object: MetricObjectPost, Shape: MetricShapeBreakdown, Periods: periodsLifetime, Description: "Reactions on the post by type"},
+	{Name: "post_reactions_like_total", Object: MetricObjectPost, Shape: MetricShapeNumber, Periods: periodsLifetime, Description: "Like reactions on the post"},
+	{Name: "post_negative_feedback", Object: MetricObjectPost, Shape: MetricShapeNumber, Periods: periodsLifetime, DeprecatedIn: "v18.0", Description: "Negative actions on the post"},
+	{Name: "post_video_views", Object: MetricObjectPost, Shape: MetricShapeNumber, Periods: periodsLifetime, Description: "Times the post's video played for at least 3 seconds"},
+	{Name: "post_video_avg_time_watched", Object: MetricObjectPost, Shape: MetricShapeNumber, Periods: periodsLifetime, Description: "Average time the post's video was viewed, in milliseconds"},
+
+	// Video metrics
+	{Name: "total_video_views", Object: MetricObjectVideo, Shape: MetricShapeNumber, Periods: periodsLifetime, Description: "Times the video played for at least 3 seconds"},
+	{Name: "total_video_views_unique", Object: MetricObjectVideo, Shape: MetricShapeNumber, Periods: periodsLifetime, Description: "People who viewed the video for at least 3 seconds"},
+	{Name: "total_video_impressions", Object: MetricObjectVideo, Shape: MetricShapeNumber, Periods: periodsLifetime, Description: "Times the video entered a screen"},
+	{Name: "total_video_avg_time_watched", Object: MetricObjectVideo, Shape: MetricShapeNumber, Periods: periodsLifetime, Description: "Average time the video was viewed, in milliseconds"},
+	{Name: "total_video_view_total_time", Object: MetricObjectVideo, Shape: MetricShapeNumber, Periods: periodsLifetime, Description: "Total time the video was viewed, in milliseconds"},
+	{Name: "total_video_reactions_by_type_total", Object: MetricObjectVideo, Shape: MetricShapeBreakdown, Periods: periodsLifetime, Description: "Reactions on the video by type"},
+}
+
+// metricIndex maps metric names to their catalog entry
+var metricIndex = indexMetrics(metricCatalog)
+
+func indexMetrics(definitions []MetricDefinition) map[string]MetricDefinition {
+	index := make(map[string]MetricDefinition, len(definitions))
+	for _, definition := range definitions {
+		index[definition.Name] = definition
+	}
+	return index
+}
+
+// LookupMetric returns the catalog entry of a metric
+func LookupMetric(name string) (MetricDefinition, bool) {
+	definition, ok := metricIndex[name]
+	return definition, ok
+}
+
+// ErrInvalidMetric is matched by errors.Is for every *MetricError
+var ErrInvalidMetric = errors.New("invalid insights metric")
+
+// MetricProblem describes why a requested metric cannot be used
+type MetricProblem struct {
+	Metric     string `json:"metric"`
+	Reason     string `json:"reason"`
+	ReplacedBy string `json:"replaced_by,omitempty"`
+}
+
+// MetricError reports metrics rejected before an insights request is sent
+type MetricError struct {
+	APIVersion string          `json:"api_version"`
+	Problems   []MetricProblem `json:"problems"`
+}
+
+func (e *MetricError) Error() string {
+	reasons := make([]string, len(e.Problems))
+	for i, problem := range e.Problems {
+		reasons[i] = fmt.Sprintf("%s: %s", problem.Metric, problem.Reason)
+		if problem.ReplacedBy != "" {
+			reasons[i] += fmt.Sprintf(" (use %s)", problem.ReplacedBy)
+		}
+	}
+	return fmt.Sprintf("invalid insights metrics for %s: %s", e.APIVersion, strings.Join(reasons, "; "))
+}
+
+// Unwrap allows errors.Is(err, ErrInvalidMetric)
+func (e *MetricError) Unwrap() error {
+	return ErrInvalidMetric
+}
+
+// ValidateMetrics checks metrics against the catalog for an object type,
+// period and API version. Metrics missing from the catalog are passed
+// through so newly released metrics can be requested.
+func ValidateMetrics(object MetricObject, metrics []string, period, apiVersion string) error {
+	metricErr := &MetricError{APIVersion: apiVersion}
+
+	for _, name := range metrics {
+		definition, ok := LookupMetric(name)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case definition.Object != object:
+			metricErr.Problems = append(metricErr.Problems, MetricProblem{
+				Metric: name,
+				Reason: fmt.Sprintf("is a %s metric, not a %s metric", definition.Object, object),
+			})
+		case !definition.AvailableIn(apiVersion):
+			problem := MetricProblem{Metric: name, ReplacedBy: definition.ReplacedBy}
+			if definition.DeprecatedIn != "" && compareAPIVersions(apiVersion, definition.DeprecatedIn) >= 0 {
+				problem.Reason = fmt.Sprintf("deprecated since %s", definition.DeprecatedIn)
+			} else {
+				problem.Reason = fmt.Sprintf("introduced in %s", definition.IntroducedIn)
+			}
+			metricErr.Problems = append(metricErr.Problems, problem)
+		case period != "" && !definition.SupportsPeriod(period):
+			metricErr.Problems = append(metricErr.Problems, MetricProblem{
+				Metric: name,
+				Reason: fmt.Sprintf("period %s not supported, use one of %s", period, strings.Join(definition.Periods, ", ")),
+			})
+		}
+	}
+
+	if len(metricErr.Problems) > 0 {
+		return metricErr
+	}
+	return nil
+}
+
+// MetricsForVersion returns the cataloged metrics of an object type that
+// exist in the given API version
+func MetricsForVersion(object MetricObject, apiVersion string) []MetricDefinition {
+	var definitions []MetricDefinition
+	for _, definition := range metricCatalog {
+		if definition.Object == object && definition.AvailableIn(apiVersion) {
+			definitions = append(definitions, definition)
+		}
+	}
+	return definitions
+}
+
+// compareAPIVersions compares Graph API versions such as "v23.0".
+// Unparseable versions compare as the newest.
+func compareAPIVersions(a, b string) int {
+	aMajor, aMinor := parseAPIVersion(a)
+	bMajor, bMinor := parseAPIVersion(b)
+
+	switch {
+	case aMajor != bMajor:
+		if aMajor < bMajor {
+			return -1
+		}
+		return 1
+	case aMinor != bMinor:
+		if aMinor < bMinor {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// parseAPIVersion splits "vMAJOR.MINOR" into its numbers
+func parseAPIVersion(version string) (int, int) {
+	major, minor, _ := strings.Cut(strings.TrimPrefix(version, "v"), ".")
+
+	majorNum, err := strconv.Atoi(major)
+	if err != nil {
+		return 1 << 30, 0
+	}
+	minorNum, _ := strconv.Atoi(minor)
+	return majorNum, minorNum
+}
+
+// MetricCatalog returns every metric known to the client
+func MetricCatalog() []MetricDefinition {
+	catalog := make([]MetricDefinition, len(metricCatalog))
+	copy(catalog, metricCatalog)
+	return catalog
+}
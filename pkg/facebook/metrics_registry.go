@@ -0,0 +1,238 @@
+package facebook
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Labels are the label names and values of a metric series
+type Labels map[string]string
+
+// Metric family types of the Prometheus text format
+const (
+	metricTypeGauge     = "gauge"
+	metricTypeCounter   = "counter"
+	metricTypeHistogram = "histogram"
+)
+
+// DefaultLatencyBuckets are the histogram bounds, in seconds, used for
+// request latencies
+var DefaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// metricFamily is a named metric and all of its labelled series
+type metricFamily struct {
+	name    string
+	help    string
+	kind    string
+	buckets []float64
+	series  map[string]*metricSample
+}
+
+// metricSample is one labelled series of a family. Histograms keep a count
+// per bucket in counts, and their sum and count in value and count.
+type metricSample struct {
+	labels string
+	value  float64
+	count  uint64
+	counts []uint64
+}
+
+// MetricsRegistry holds metrics and renders them in the Prometheus text
+// exposition format. It is safe for concurrent use.
+type MetricsRegistry struct {
+	mu       sync.Mutex
+	families map[string]*metricFamily
+}
+
+// NewMetricsRegistry creates an empty registry
+func NewMetricsRegistry() *MetricsRegistry {
+	return &MetricsRegistry{families: make(map[string]*metricFamily)}
+}
+
+// SetGauge sets the value of a gauge series. Like every recording method
+// it does nothing on a nil registry, so instrumentation can stay unconditional.
+func (r *MetricsRegistry) SetGauge(name, help string, labels Labels, value float64) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sample(name, help, metricTypeGauge, nil, labels).value = value
+}
+
+// AddCounter increases a counter series by delta
+func (r *MetricsRegistry) AddCounter(name, help string, labels Labels, delta float64) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sample(name, help, metricTypeCounter, nil, labels).value += delta
+}
+
+// Observe records a value in a histogram series with DefaultLatencyBuckets
+func (r *MetricsRegistry) Observe(name, help string, labels Labels, value float64) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sample := r.sample(name, help, metricTypeHistogram, DefaultLatencyBuckets, labels)
+	buckets := r.families[name].buckets
+	if sample.counts == nil {
+		sample.counts = make([]uint64, len(buckets))
+	}
+	for i, bound := range buckets {
+		if value <= bound {
+			sample.counts[i]++
+		}
+	}
+	sample.value += value
+	sample.count++
+}
+
+// DeleteSeries removes a series, e.g. an info gauge whose labels changed
+func (r *MetricsRegistry) DeleteSeries(name string, labels Labels) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if family, ok := r.families[name]; ok {
+		delete(family.series, formatLabels(labels))
+	}
+}
+
+// sample returns the series of a family, creating both as needed. The
+// caller must hold r.mu.
+func (r *MetricsRegistry) sample(name, help, kind string, buckets []float64, labels Labels) *metricSample {
+	family, ok := r.families[name]
+	if !ok {
+		family = &metricFamily{name: name, help: help, kind: kind, buckets: buckets, series: make(map[string]*metricSample)}
+		r.families[name] = family
+	}
+
+	key := formatLabels(labels)
+	sample, ok := family.series[key]
+	if !ok {
+		sample = &metricSample{labels: key}
+		family.series[key] = sample
+	}
+	return sample
+}
+
+// WriteText writes every metric in the Prometheus text format, ordered by
+// name and labels
+func (r *MetricsRegistry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buf := bufio.NewWriter(w)
+	for _, name := range names {
+		family := r.families[name]
+		fmt.Fprintf(buf, "# HELP %s %s\n", name, helpEscaper.Replace(family.help))
+		fmt.Fprintf(buf, "# TYPE %s %s\n", name, family.kind)
+
+		keys := make([]string, 0, len(family.series))
+		for key := range family.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			sample := family.series[key]
+			if family.kind != metricTypeHistogram {
+				fmt.Fprintf(buf, "%s%s %s\n", name, key, formatMetricValue(sample.value))
+				continue
+			}
+
+			for i, bound := range family.buckets {
+				fmt.Fprintf(buf, "%s_bucket%s %d\n", name, withLabel(key, "le", formatMetricValue(bound)), sample.counts[i])
+			}
+			fmt.Fprintf(buf, "%s_bucket%s %d\n", name, withLabel(key, "le", "+Inf"), sample.count)
+			fmt.Fprintf(buf, "%s_sum%s %s\n", name, key, formatMetricValue(sample.value))
+			fmt.Fprintf(buf, "%s_count%s %d\n", name, key, sample.count)
+		}
+	}
+	return buf.Flush()
+}
+
+// ServeHTTP serves the registry as a Prometheus scrape target
+func (r *MetricsRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	r.WriteText(w)
+}
+
+// formatLabels renders labels as {name="value",...} sorted by name
+func formatLabels(labels Labels) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `%s="%s"`, name, labelValueEscaper.Replace(labels[name]))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// withLabel appends a label to formatted labels
+func withLabel(formatted, name, value string) string {
+	label := fmt.Sprintf(`%s="%s"`, name, labelValueEscaper.Replace(value))
+	if formatted == "" {
+		return "{" + label + "}"
+	}
+	return formatted[:len(formatted)-1] + "," + label + "}"
+}
+
+// labelValueEscaper escapes label values as the text format requires
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// helpEscaper escapes HELP text as the text format requires
+var helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+// formatMetricValue renders a sample value, spelling out infinities and NaN
+func formatMetricValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
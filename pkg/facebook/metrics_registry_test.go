@@ -0,0 +1,143 @@
+package facebook
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetricsRegistryWritesTextFormat(t *testing.T) {
+	registry := NewMetricsRegistry()
+	registry.SetGauge("facebook_page_fan_count", "People who like the page", Labels{"page_id": "2"}, 20)
+	registry.SetGauge("facebook_page_fan_count", "People who like the page", Labels{"page_id": "1"}, 1500)
+	registry.SetGauge("facebook_page_info", "Page metadata", Labels{"page_id": "1", "page_name": "Say \"hi\"\\"}, 1)
+
+	var out strings.Builder
+	if err := registry.WriteText(&out); err != nil {
+		t.Fatalf("Writing metrics: %v", err)
+	}
+
+	expected := `# HELP facebook_page_fan_count People who like the page
+# TYPE facebook_page_fan_count gauge
+facebook_page_fan_count{page_id="1"} 1500
+facebook_page_fan_count{page_id="2"} 20
+# HELP facebook_page_info Page metadata
+# TYPE facebook_page_info gauge
+facebook_page_info{page_id="1",page_name="Say \"hi\"\\"} 1
+`
+	if out.String() != expected {
+		t.Errorf("Unexpected exposition:\n%s", out.String())
+	}
+}
+
+func TestRoutersRecordRequestAndGraphMetrics(t *testing.T) {
+	graph := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("X-App-Usage", `{"call_count":28,"total_time":12,"total_cputime":9}`)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"message":"Unsupported get request","type":"GraphMethodException","code":100}}`))
+	}))
+	defer graph.Close()
+
+	registry := NewMetricsRegistry()
+	router := NewSimpleRouter("token")
+	router.defaultClient.BaseURL = graph.URL
+	router.SetMetricsRegistry(registry)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/api/pages/12345/posts", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/nope", nil))
+
+	var out strings.Builder
+	registry.WriteText(&out)
+	for _, expected := range []string{
+		`http_requests_total{method="GET",route="/api/pages/{pageId}/posts",status="500"} 1`,
+		`http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`facebook_graph_requests_total{endpoint="/{id}/posts",method="GET",status="400"} 1`,
+		`facebook_graph_errors_total{code="100",endpoint="/{id}/posts"} 1`,
+		`facebook_graph_rate_limit_usage_percent{id="",metric="call_count",scope="app"} 28`,
+		`facebook_graph_request_duration_seconds_count{endpoint="/{id}/posts",method="GET"} 1`,
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected %s in:\n%s", expected, out.String())
+		}
+	}
+
+	// Typed errors keep the original message format
+	_, err := router.defaultClient.GetPage("12345")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != 100 || err.Error() != "API error: Unsupported get request (code: 100)" {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+func TestRouterRecordsUnmatchedRequests(t *testing.T) {
+	registry := NewMetricsRegistry()
+	exporter := NewInMemoryExporter()
+
+	router := NewRouter("token")
+	router.SetMetricsRegistry(registry)
+	router.SetTracer(NewTracer("test", exporter))
+	handler := router.SetupRoutes()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/nope", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("DELETE", "/api/pages/12345", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405, got %d", rec.Code)
+	}
+
+	var out strings.Builder
+	registry.WriteText(&out)
+	for _, expected := range []string{
+		`http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`http_requests_total{method="DELETE",route="unmatched",status="405"} 1`,
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected %s in:\n%s", expected, out.String())
+		}
+	}
+
+	if spans := exporter.Spans(); len(spans) != 2 || spans[0].Kind != SpanKindServer || spans[1].Kind != SpanKindServer {
+		t.Errorf("Expected a server span for each unmatched request, got %+v", spans)
+	}
+}
+
+func TestSimpleRouterLabelsUnknownPathsUnmatched(t *testing.T) {
+	registry := NewMetricsRegistry()
+	exporter := NewInMemoryExporter()
+
+	router := NewSimpleRouter("token")
+	router.SetMetricsRegistry(registry)
+	router.SetTracer(NewTracer("test", exporter))
+
+	// Scanner paths and extra segments must not become labels of their own
+	for _, path := range []string{"/wp-login.php", "/favicon.ico", "/api/pages/1/posts/x/y", "/api/unknown/1"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected 404 for %s, got %d", path, rec.Code)
+		}
+	}
+
+	var out strings.Builder
+	registry.WriteText(&out)
+	if !strings.Contains(out.String(), `http_requests_total{method="GET",route="unmatched",status="404"} 4`) {
+		t.Errorf("Expected every unknown path to share the unmatched label:\n%s", out.String())
+	}
+	if strings.Count(out.String(), "http_requests_total{") != 1 {
+		t.Errorf("Expected a single request series:\n%s", out.String())
+	}
+
+	for _, span := range exporter.Spans() {
+		if span.Name != "GET unmatched" {
+			t.Errorf("Expected unknown paths to share a span name, got %q", span.Name)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package facebook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// defaultKPIInsights are the daily insights exported when none are configured
+var defaultKPIInsights = []string{
+	"page_impressions",
+	"page_impressions_unique",
+	"page_post_engagements",
+	"page_views_total",
+	"page_daily_follows_unique",
+	"page_daily_unfollows_unique",
+}
+
+// PageMetricsConfig configures a PageMetricsPoller
+type PageMetricsConfig struct {
+	// PageIDs are the pages whose KPIs are exported
+	PageIDs []string
+	// Insights are daily page metrics exported alongside the page counts
+	Insights []string
+	// Interval between refreshes (default 5 minutes)
+	Interval time.Duration
+	// Logf reports refresh failures; defaults to log.Printf
+	Logf func(format string, args ...interface{})
+}
+
+// PageMetricsPoller refreshes page KPI gauges in a MetricsRegistry in the
+// background, so scrapes are served from memory without calling Graph
+type PageMetricsPoller struct {
+	client   *Client
+	registry *MetricsRegistry
+	config   PageMetricsConfig
+
+	mu sync.Mutex
+	// names are the page names of the exported facebook_page_info series
+	names map[string]string
+}
+
+// NewPageMetricsPoller creates a poller exporting KPIs of config.PageIDs
+func NewPageMetricsPoller(client *Client, registry *MetricsRegistry, config PageMetricsConfig) *PageMetricsPoller {
+	if len(config.Insights) == 0 {
+		config.Insights = defaultKPIInsights
+	}
+	if config.Interval <= 0 {
+		config.Interval = 5 * time.Minute
+	}
+	if config.Logf == nil {
+		config.Logf = log.Printf
+	}
+
+	return &PageMetricsPoller{client: client, registry: registry, config: config, names: make(map[string]string)}
+}
+
+// Run refreshes immediately and then every Interval until ctx is done
+func (p *PageMetricsPoller) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.Refresh(ctx); err != nil {
+			p.config.Logf("page metrics: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh updates the gauges of every page. Pages that fail keep their
+// previous values and have facebook_page_refresh_success set to 0.
+func (p *PageMetricsPoller) Refresh(ctx context.Context) error {
+	client := p.client.WithContext(ctx)
+
+	var errs []error
+	for _, pageID := range p.config.PageIDs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		err := p.refreshPage(client, pageID)
+		labels := Labels{"page_id": pageID}
+
+		success := 1.0
+		if err != nil {
+			success = 0
+			errs = append(errs, fmt.Errorf("page %s: %w", pageID, err))
+		} else {
+			p.registry.SetGauge("facebook_page_last_refresh_timestamp_seconds",
+				"Unix time of the last successful refresh of the page's metrics", labels, float64(time.Now().Unix()))
+		}
+		p.registry.SetGauge("facebook_page_refresh_success",
+			"Whether the last refresh of the page's metrics succeeded", labels, success)
+	}
+	return errors.Join(errs...)
+}
+
+// refreshPage exports the counts and latest daily insights of one page
+func (p *PageMetricsPoller) refreshPage(client *Client, pageID string) error {
+	page, err := client.GetPage(pageID, "id", "name", "fan_count", "followers_count", "talking_about_count")
+	if err != nil {
+		return err
+	}
+
+	p.setPageInfo(pageID, page.Name)
+
+	labels := Labels{"page_id": pageID}
+	p.registry.SetGauge("facebook_page_fan_count", "People who like the page", labels, float64(page.FanCount))
+	p.registry.SetGauge("facebook_page_followers_count", "People who follow the page", labels, float64(page.FollowersCount))
+	p.registry.SetGauge("facebook_page_talking_about_count", "People talking about the page", labels, float64(page.TalkingAboutCount))
+
+	// Daily values are published with a delay, so look back a few days for
+	// the most recent one
+	until := time.Now().UTC()
+	since := until.AddDate(0, 0, -3)
+	insights, err := client.GetPageInsights(pageID, p.config.Insights, PeriodDay, &since, &until)
+	if err != nil {
+		return err
+	}
+
+	for _, insight := range insights.Data {
+		for i := len(insight.Values) - 1; i >= 0; i-- {
+			value := insight.Values[i]
+			if value.Value == nil {
+				continue
+			}
+
+			p.registry.SetGauge("facebook_page_insight_value", "Latest daily value of a page insights metric",
+				Labels{"page_id": pageID, "metric": insight.Name, "period": insight.Period}, value.Float())
+			break
+		}
+	}
+	return nil
+}
+
+// setPageInfo exports the page name, removing the series of a previous name
+// so a renamed page does not keep reporting both
+func (p *PageMetricsPoller) setPageInfo(pageID, name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if previous, ok := p.names[pageID]; ok && previous != name {
+		p.registry.DeleteSeries("facebook_page_info", Labels{"page_id": pageID, "page_name": previous})
+	}
+	p.names[pageID] = name
+	p.registry.SetGauge("facebook_page_info", "Page metadata; always 1", Labels{"page_id": pageID, "page_name": name}, 1)
+}
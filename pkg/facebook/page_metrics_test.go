@@ -0,0 +1,119 @@
+package facebook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakePageKPIs serves the counts and insights of page_1 and fails page_bad
+type fakePageKPIs struct {
+	mu   sync.Mutex
+	name string
+}
+
+func (f *fakePageKPIs) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(req.URL.Path, "/"+DefaultAPIVersion+"/")
+	switch path {
+	case "page_1":
+		fmt.Fprintf(w, `{"id":"page_1","name":%q,"fan_count":1500,"followers_count":1600,"talking_about_count":40}`, f.name)
+	case "page_1/insights":
+		// Today's value is not published yet
+		fmt.Fprint(w, `{"data":[{"name":"page_impressions","period":"day","values":[
+			{"value":10,"end_time":"2024-03-02T08:00:00+0000"},
+			{"value":12,"end_time":"2024-03-03T08:00:00+0000"},
+			{"value":null,"end_time":"2024-03-04T08:00:00+0000"}]}]}`)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{"error":{"message":"Service unavailable","type":"OAuthException","code":2}}`)
+	}
+}
+
+func (f *fakePageKPIs) rename(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.name = name
+}
+
+func TestPageMetricsPollerRefresh(t *testing.T) {
+	fake := &fakePageKPIs{name: "Launch"}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client := NewClient("test_token")
+	client.BaseURL = server.URL
+
+	registry := NewMetricsRegistry()
+	poller := NewPageMetricsPoller(client, registry, PageMetricsConfig{
+		PageIDs:  []string{"page_1", "page_bad"},
+		Insights: []string{"page_impressions"},
+		Logf:     t.Logf,
+	})
+
+	err := poller.Refresh(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "page page_bad: ") {
+		t.Errorf("Expected page_bad to fail, got %v", err)
+	}
+
+	text := func() string {
+		var out strings.Builder
+		if err := registry.WriteText(&out); err != nil {
+			t.Fatalf("Writing metrics: %v", err)
+		}
+		return out.String()
+	}
+
+	exposition := text()
+	for _, line := range []string{
+		`facebook_page_fan_count{page_id="page_1"} 1500`,
+		`facebook_page_followers_count{page_id="page_1"} 1600`,
+		`facebook_page_talking_about_count{page_id="page_1"} 40`,
+		`facebook_page_info{page_id="page_1",page_name="Launch"} 1`,
+		`facebook_page_insight_value{metric="page_impressions",page_id="page_1",period="day"} 12`,
+		`facebook_page_refresh_success{page_id="page_1"} 1`,
+		`facebook_page_refresh_success{page_id="page_bad"} 0`,
+	} {
+		if !strings.Contains(exposition, line+"\n") {
+			t.Errorf("Expected %s in:\n%s", line, exposition)
+		}
+	}
+	if strings.Contains(exposition, `facebook_page_last_refresh_timestamp_seconds{page_id="page_bad"}`) {
+		t.Error("Expected no refresh timestamp for the failed page")
+	}
+
+	// A renamed page replaces its info series
+	fake.rename("Relaunch")
+	poller.Refresh(context.Background())
+	exposition = text()
+	if strings.Contains(exposition, `page_name="Launch"`) || !strings.Contains(exposition, `facebook_page_info{page_id="page_1",page_name="Relaunch"} 1`) {
+		t.Errorf("Expected only the new page name to be exported:\n%s", exposition)
+	}
+}
+
+func TestPageMetricsPollerCancelsGraphCalls(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The request blocks until the refresh's context is cancelled
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		cancel()
+		<-req.Context().Done()
+	}))
+	defer server.Close()
+
+	client := NewClient("test_token")
+	client.BaseURL = server.URL
+
+	poller := NewPageMetricsPoller(client, NewMetricsRegistry(), PageMetricsConfig{PageIDs: []string{"page_1", "page_2"}, Logf: t.Logf})
+	if err := poller.Refresh(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the refresh to be cancelled, got %v", err)
+	}
+}
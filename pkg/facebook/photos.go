@@ -1,47 +1,92 @@
 package facebook
 
 import (
-	"bytes"
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"io"
-	"mime/multipart"
 	"net/url"
 	"os"
+	"time"
 )
 
+// photoRollbackTimeout bounds the deletes of a failed multi-photo post, which
+// run even when the post's context was cancelled
+const photoRollbackTimeout = 30 * time.Second
+
 // UploadPhoto uploads a photo to a Facebook page
 func (c *Client) UploadPhoto(pageID string, imagePath string, message string, published bool) (*PhotoResponse, error) {
+	return c.UploadPhotoContext(c.requestContext(), pageID, imagePath, message, published, nil)
+}
+
+// UploadPhotoContext uploads a photo file to a Facebook page, streaming it
+// from disk with a known Content-Length
+func (c *Client) UploadPhotoContext(ctx context.Context, pageID string, imagePath string, message string, published bool, opts *UploadOptions) (*PhotoResponse, error) {
 	file, err := os.Open(imagePath)
 	if err != nil {
 		return nil, fmt.Errorf("opening image file: %w", err)
 	}
 	defer file.Close()
 
-	return c.UploadPhotoFromReader(pageID, file, message, published)
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("reading image file info: %w", err)
+	}
+
+	fileOpts := UploadOptions{}
+	if opts != nil {
+		fileOpts = *opts
+	}
+	fileOpts.Size = stat.Size()
+
+	return c.UploadPhotoFromReaderContext(ctx, pageID, file, message, published, &fileOpts)
 }
 
-// UploadPhotoFromReader uploads a photo from an io.Reader to a Facebook page
+// UploadPhotoFromReader uploads a photo from an io.Reader to a Facebook page.
+// The image type is sniffed from its content and checked against
+// DefaultPhotoLimits; rejected images return a *MediaValidationError.
 func (c *Client) UploadPhotoFromReader(pageID string, reader io.Reader, message string, published bool) (*PhotoResponse, error) {
-	var body bytes.Buffer
-	writer := multipart.NewWriter(&body)
+	return c.UploadPhotoFromReaderContext(c.requestContext(), pageID, reader, message, published, nil)
+}
 
-	// Add the image file
-	part, err := writer.CreateFormFile("source", "image.jpg")
-	if err != nil {
-		return nil, fmt.Errorf("creating form file: %w", err)
+// UploadPhotoFromReaderContext streams a photo from an io.Reader to a Facebook
+// page without buffering it in memory. Only the first bytes are inspected to
+// detect the image type and dimensions; the size limit is enforced up front
+// when opts.Size is set and while streaming otherwise.
+func (c *Client) UploadPhotoFromReaderContext(ctx context.Context, pageID string, reader io.Reader, message string, published bool, opts *UploadOptions) (*PhotoResponse, error) {
+	if opts == nil {
+		opts = &UploadOptions{}
+	}
+
+	limits := DefaultPhotoLimits
+	if opts.Limits != nil {
+		limits = *opts.Limits
 	}
 
-	_, err = io.Copy(part, reader)
+	// Peek at the leading bytes without consuming them
+	buffered := bufio.NewReaderSize(reader, sniffLen)
+	header, err := buffered.Peek(sniffLen)
+	size := opts.Size
+	if err == io.EOF {
+		// The whole image fits in the sniff buffer
+		size = int64(len(header))
+	} else if err != nil && err != bufio.ErrBufferFull {
+		return nil, fmt.Errorf("reading image data: %w", err)
+	}
+
+	info, err := InspectImage(header, size)
 	if err != nil {
-		return nil, fmt.Errorf("copying image data: %w", err)
+		return nil, err
 	}
 
-	// Add message if provided
-	if message != "" {
-		err = writer.WriteField("message", message)
-		if err != nil {
-			return nil, fmt.Errorf("writing message field: %w", err)
-		}
+	if err := info.Validate(limits); err != nil {
+		return nil, err
+	}
+
+	var body io.Reader = buffered
+	if size <= 0 && limits.MaxBytes > 0 {
+		body = &maxBytesReader{reader: buffered, limit: limits.MaxBytes}
 	}
 
 	// Add published status
@@ -49,33 +94,33 @@ func (c *Client) UploadPhotoFromReader(pageID string, reader io.Reader, message
 	if !published {
 		publishedStr = "false"
 	}
-	err = writer.WriteField("published", publishedStr)
-	if err != nil {
-		return nil, fmt.Errorf("writing published field: %w", err)
+
+	fields := []formField{
+		{name: "published", value: publishedStr},
+		{name: "access_token", value: c.AccessToken},
 	}
 
-	// Add access token
-	err = writer.WriteField("access_token", c.AccessToken)
-	if err != nil {
-		return nil, fmt.Errorf("writing access token field: %w", err)
+	// Add message if provided
+	if message != "" {
+		fields = append(fields, formField{name: "message", value: message})
 	}
 
-	err = writer.Close()
-	if err != nil {
-		return nil, fmt.Errorf("closing multipart writer: %w", err)
+	file := &multipartFile{
+		fieldName:   "source",
+		filename:    info.Filename("image"),
+		contentType: info.ContentType,
+		reader:      body,
+		size:        size,
 	}
 
-	// Create the request
 	endpoint := fmt.Sprintf("%s/photos", pageID)
-	apiURL := c.buildURL(endpoint)
-
-	req, err := c.HTTPClient.Post(apiURL, writer.FormDataContentType(), &body)
+	resp, err := c.postMultipart(ctx, c.buildURL(endpoint), fields, file, opts.Progress)
 	if err != nil {
 		return nil, fmt.Errorf("making photo upload request: %w", err)
 	}
 
 	var photoResp PhotoResponse
-	if err := c.handleResponse(req, &photoResp); err != nil {
+	if err := c.handleResponse(resp, &photoResp); err != nil {
 		return nil, err
 	}
 
@@ -160,3 +205,97 @@ func (c *Client) DeletePhoto(photoID string) error {
 
 	return nil
 }
+
+// MultiPhotoItem is one image of a multi-photo post. Exactly one of Path,
+// Reader or URL is used, in that order of precedence.
+type MultiPhotoItem struct {
+	Path   string
+	Reader io.Reader
+	URL    string
+	// Caption is shown on the individual photo
+	Caption string
+}
+
+// MultiPhotoPostResponse represents a post created with several photos
+type MultiPhotoPostResponse struct {
+	ID       string   `json:"id"`
+	PhotoIDs []string `json:"photo_ids"`
+}
+
+// CreateMultiPhotoPost publishes a single feed post with several photos
+func (c *Client) CreateMultiPhotoPost(pageID string, message string, photos []MultiPhotoItem) (*MultiPhotoPostResponse, error) {
+	return c.CreateMultiPhotoPostContext(c.requestContext(), pageID, message, photos)
+}
+
+// CreateMultiPhotoPostContext uploads each photo unpublished and creates a
+// feed post attaching them. If an upload or the final post fails, the photos
+// uploaded so far are deleted again.
+func (c *Client) CreateMultiPhotoPostContext(ctx context.Context, pageID string, message string, photos []MultiPhotoItem) (*MultiPhotoPostResponse, error) {
+	if len(photos) == 0 {
+		return nil, fmt.Errorf("at least one photo is required")
+	}
+
+	photoIDs := make([]string, 0, len(photos))
+	for i, item := range photos {
+		photo, err := c.uploadUnpublishedPhoto(ctx, pageID, item)
+		if err != nil {
+			return nil, c.rollbackPhotos(ctx, photoIDs, fmt.Errorf("uploading photo %d: %w", i+1, err))
+		}
+		photoIDs = append(photoIDs, photo.ID)
+	}
+
+	params := url.Values{}
+	if message != "" {
+		params.Set("message", message)
+	}
+	for i, id := range photoIDs {
+		params.Set(fmt.Sprintf("attached_media[%d]", i), fmt.Sprintf(`{"media_fbid":"%s"}`, id))
+	}
+
+	endpoint := fmt.Sprintf("%s/feed", pageID)
+	resp, err := c.makeRequestContext(ctx, "POST", endpoint, params, nil)
+	if err != nil {
+		return nil, c.rollbackPhotos(ctx, photoIDs, fmt.Errorf("creating multi-photo post: %w", err))
+	}
+
+	var postResp PostResponse
+	if err := c.handleResponse(resp, &postResp); err != nil {
+		return nil, c.rollbackPhotos(ctx, photoIDs, fmt.Errorf("creating multi-photo post: %w", err))
+	}
+
+	return &MultiPhotoPostResponse{
+		ID:       postResp.ID,
+		PhotoIDs: photoIDs,
+	}, nil
+}
+
+// uploadUnpublishedPhoto uploads one item of a multi-photo post with published=false
+func (c *Client) uploadUnpublishedPhoto(ctx context.Context, pageID string, item MultiPhotoItem) (*PhotoResponse, error) {
+	switch {
+	case item.Path != "":
+		return c.UploadPhotoContext(ctx, pageID, item.Path, item.Caption, false, nil)
+	case item.Reader != nil:
+		return c.UploadPhotoFromReaderContext(ctx, pageID, item.Reader, item.Caption, false, nil)
+	case item.URL != "":
+		return c.WithContext(ctx).UploadPhotoByURL(pageID, item.URL, item.Caption, false)
+	}
+	return nil, fmt.Errorf("photo path, reader or URL is required")
+}
+
+// rollbackPhotos deletes photos uploaded for a post that could not be created.
+// The deletes outlive a cancelled ctx, e.g. when the caller disconnected
+// mid-upload, so no unpublished photos are left behind. Failed deletions are
+// joined to the original error.
+func (c *Client) rollbackPhotos(ctx context.Context, photoIDs []string, cause error) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), photoRollbackTimeout)
+	defer cancel()
+	client := c.WithContext(ctx)
+
+	errs := []error{cause}
+	for _, id := range photoIDs {
+		if err := client.DeletePhoto(id); err != nil {
+			errs = append(errs, fmt.Errorf("rolling back photo %s: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,181 @@
+package facebook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Poller watches pages via GetPosts/GetPostComments and feeds new activity
+// into a Dispatcher
+type Poller struct {
+	Client     *Client
+	Dispatcher *Dispatcher
+	PageIDs    []string
+
+	// Interval between polls
+	Interval time.Duration
+	// PostLimit is the number of most recent posts checked on each poll
+	PostLimit int
+	// CommentLimit is the number of most recent comments checked per post
+	CommentLimit int
+	// EmitExisting emits events for content already present on the first poll
+	EmitExisting bool
+	// OnError is called with errors from each poll run by Run; defaults to log.Printf
+	OnError func(err error)
+
+	primed   map[string]bool
+	posts    map[string]map[string]bool
+	comments map[string]map[string]bool
+}
+
+// NewPoller creates a poller for the given pages
+func NewPoller(client *Client, dispatcher *Dispatcher, pageIDs ...string) *Poller {
+	return &Poller{
+		Client:       client,
+		Dispatcher:   dispatcher,
+		PageIDs:      pageIDs,
+		Interval:     time.Minute,
+		PostLimit:    10,
+		CommentLimit: 25,
+		primed:       make(map[string]bool),
+		posts:        make(map[string]map[string]bool),
+		comments:     make(map[string]map[string]bool),
+	}
+}
+
+// Run polls every Interval until the context is cancelled
+func (p *Poller) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	for {
+		// Errors are per page and per sink; keep polling and let the
+		// dead-letter store capture failed deliveries.
+		if err := p.Poll(ctx); err != nil && ctx.Err() == nil {
+			if p.OnError != nil {
+				p.OnError(err)
+			} else {
+				log.Printf("poller: %v", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll checks every page once and dispatches events for new posts and comments
+func (p *Poller) Poll(ctx context.Context) error {
+	var errs []error
+	for _, pageID := range p.PageIDs {
+		if err := p.pollPage(ctx, pageID); err != nil {
+			errs = append(errs, fmt.Errorf("polling page %s: %w", pageID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// pollPage checks a single page
+func (p *Poller) pollPage(ctx context.Context, pageID string) error {
+	postsResp, err := p.Client.GetPosts(pageID, p.PostLimit, "id", "message", "created_time", "permalink_url")
+	if err != nil {
+		return err
+	}
+
+	emit := p.primed[pageID] || p.EmitExisting
+	seenPosts := p.posts[pageID]
+	currentPosts := make(map[string]bool, len(postsResp.Data))
+
+	var errs []error
+
+	// Posts are returned newest first; emit oldest first
+	for i := len(postsResp.Data) - 1; i >= 0; i-- {
+		post := postsResp.Data[i]
+		currentPosts[post.ID] = true
+
+		if emit && !seenPosts[post.ID] {
+			event := Event{
+				ID:         string(EventPostCreated) + ":" + post.ID,
+				Type:       EventPostCreated,
+				PageID:     pageID,
+				PostID:     post.ID,
+				OccurredAt: post.CreatedTime.Time,
+				Post:       &post,
+			}
+			if err := p.Dispatcher.Dispatch(ctx, event); err != nil {
+				errs = append(errs, err)
+			}
+			// Every comment of a new post is new, even if fetching them fails now
+			if p.comments[post.ID] == nil {
+				p.comments[post.ID] = make(map[string]bool)
+			}
+		}
+
+		if err := p.pollComments(ctx, pageID, post.ID, emit); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Forget posts that dropped out of the polling window
+	for postID := range seenPosts {
+		if !currentPosts[postID] {
+			delete(p.comments, postID)
+		}
+	}
+
+	p.posts[pageID] = currentPosts
+	p.primed[pageID] = true
+
+	return errors.Join(errs...)
+}
+
+// pollComments checks a single post for new comments
+func (p *Poller) pollComments(ctx context.Context, pageID, postID string, emit bool) error {
+	commentsResp, err := p.Client.GetPostComments(postID, p.CommentLimit, "reverse_chronological")
+	if err != nil {
+		return fmt.Errorf("getting comments for post %s: %w", postID, err)
+	}
+
+	// Comments of a post seen before whose comments were never fetched, e.g.
+	// after a failure while priming, seed the post instead of being emitted
+	seen := p.comments[postID]
+	if seen == nil {
+		seen = make(map[string]bool)
+		p.comments[postID] = seen
+		emit = false
+	}
+
+	var errs []error
+	for i := len(commentsResp.Data) - 1; i >= 0; i-- {
+		comment := commentsResp.Data[i]
+		if seen[comment.ID] {
+			continue
+		}
+		seen[comment.ID] = true
+
+		if !emit {
+			continue
+		}
+
+		event := Event{
+			ID:         string(EventCommentCreated) + ":" + comment.ID,
+			Type:       EventCommentCreated,
+			PageID:     pageID,
+			PostID:     postID,
+			CommentID:  comment.ID,
+			OccurredAt: comment.CreatedTime.Time,
+			Comment:    &comment,
+		}
+		if err := p.Dispatcher.Dispatch(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
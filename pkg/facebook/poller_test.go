@@ -0,0 +1,176 @@
+package facebook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeFeed serves the posts and comments of one page
+type fakeFeed struct {
+	mu       sync.Mutex
+	posts    []Post
+	comments map[string][]Comment
+	// failComments fails the comment requests of these posts
+	failComments map[string]bool
+}
+
+func (f *fakeFeed) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case strings.HasSuffix(req.URL.Path, "/posts"):
+		json.NewEncoder(w).Encode(PostsResponse{Data: f.posts})
+	case strings.HasSuffix(req.URL.Path, "/comments"):
+		segments := strings.Split(req.URL.Path, "/")
+		postID := segments[len(segments)-2]
+		if f.failComments[postID] {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error":{"message":"Service unavailable","type":"OAuthException","code":2}}`))
+			return
+		}
+		json.NewEncoder(w).Encode(CommentsResponse{Data: f.comments[postID]})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// add prepends a post (newest first) or a comment (newest first)
+func (f *fakeFeed) add(postID, commentID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if commentID == "" {
+		f.posts = append([]Post{{ID: postID}}, f.posts...)
+		return
+	}
+	f.comments[postID] = append([]Comment{{ID: commentID}}, f.comments[postID]...)
+}
+
+// drain returns the IDs of the events buffered in a channel sink
+func drain(sink *ChannelSink) []string {
+	var ids []string
+	for {
+		select {
+		case event := <-sink.Events():
+			ids = append(ids, event.ID)
+		default:
+			return ids
+		}
+	}
+}
+
+func newTestPoller(t *testing.T, feed *fakeFeed) (*Poller, *ChannelSink) {
+	server := httptest.NewServer(feed)
+	t.Cleanup(server.Close)
+
+	client := NewClient("test_token")
+	client.BaseURL = server.URL
+
+	sink := NewChannelSink("test", 16)
+	dispatcher := NewDispatcher(NewDeadLetterStore(filepath.Join(t.TempDir(), "deadletter.ndjson")))
+	dispatcher.AddSink(sink)
+
+	return NewPoller(client, dispatcher, "page"), sink
+}
+
+func TestPollerEmitsOnlyNewActivity(t *testing.T) {
+	feed := &fakeFeed{comments: make(map[string][]Comment)}
+	feed.add("page_1", "")
+	feed.add("page_1", "c1")
+
+	poller, sink := newTestPoller(t, feed)
+
+	// The first poll primes the poller with existing content
+	if err := poller.Poll(context.Background()); err != nil {
+		t.Fatalf("Expected first poll to succeed, got %v", err)
+	}
+	if ids := drain(sink); len(ids) != 0 {
+		t.Fatalf("Expected no events when priming, got %v", ids)
+	}
+
+	feed.add("page_2", "")
+	feed.add("page_2", "c2")
+	feed.add("page_1", "c3")
+
+	if err := poller.Poll(context.Background()); err != nil {
+		t.Fatalf("Expected second poll to succeed, got %v", err)
+	}
+	got := strings.Join(drain(sink), ",")
+	want := "comment.created:c3,post.created:page_2,comment.created:c2"
+	if got != want {
+		t.Errorf("Expected events %s, got %s", want, got)
+	}
+
+	// Nothing changed, so nothing is emitted again
+	if err := poller.Poll(context.Background()); err != nil {
+		t.Fatalf("Expected third poll to succeed, got %v", err)
+	}
+	if ids := drain(sink); len(ids) != 0 {
+		t.Errorf("Expected no repeated events, got %v", ids)
+	}
+}
+
+func TestPollerEmitExisting(t *testing.T) {
+	feed := &fakeFeed{comments: make(map[string][]Comment)}
+	feed.add("page_1", "")
+	feed.add("page_1", "c1")
+
+	poller, sink := newTestPoller(t, feed)
+	poller.EmitExisting = true
+
+	if err := poller.Poll(context.Background()); err != nil {
+		t.Fatalf("Expected poll to succeed, got %v", err)
+	}
+	if got := strings.Join(drain(sink), ","); got != "post.created:page_1,comment.created:c1" {
+		t.Errorf("Expected existing post and comment to be emitted, got %s", got)
+	}
+}
+
+func TestPollerSeedsCommentsThatFailedWhilePriming(t *testing.T) {
+	feed := &fakeFeed{comments: make(map[string][]Comment), failComments: map[string]bool{"page_1": true}}
+	feed.add("page_1", "")
+	feed.add("page_1", "c1")
+
+	poller, sink := newTestPoller(t, feed)
+
+	if err := poller.Poll(context.Background()); err == nil {
+		t.Fatal("Expected the failed comments to be reported")
+	}
+
+	// The existing comment is only seeded once it can be fetched
+	feed.mu.Lock()
+	feed.failComments = nil
+	feed.mu.Unlock()
+	if err := poller.Poll(context.Background()); err != nil {
+		t.Fatalf("Expected second poll to succeed, got %v", err)
+	}
+	if ids := drain(sink); len(ids) != 0 {
+		t.Fatalf("Expected existing comments not to be emitted, got %v", ids)
+	}
+
+	// A new post's comments are emitted even when the first fetch fails
+	feed.add("page_2", "")
+	feed.add("page_2", "c2")
+	feed.mu.Lock()
+	feed.failComments = map[string]bool{"page_2": true}
+	feed.mu.Unlock()
+	poller.Poll(context.Background())
+
+	feed.add("page_1", "c3")
+	feed.mu.Lock()
+	feed.failComments = nil
+	feed.mu.Unlock()
+	if err := poller.Poll(context.Background()); err != nil {
+		t.Fatalf("Expected fourth poll to succeed, got %v", err)
+	}
+	if got := strings.Join(drain(sink), ","); got != "post.created:page_2,comment.created:c3,comment.created:c2" {
+		t.Errorf("Expected only new activity, got %s", got)
+	}
+}
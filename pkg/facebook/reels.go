@@ -0,0 +1,258 @@
+package facebook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Reel publishing states accepted by the video_reels finish phase
+const (
+	VideoStatePublished = "PUBLISHED"
+	VideoStateDraft     = "DRAFT"
+	VideoStateScheduled = "SCHEDULED"
+)
+
+// ReelOptions describes how a reel is published
+type ReelOptions struct {
+	Description string
+	Title       string
+
+	// Draft saves the reel without publishing it
+	Draft bool
+	// ScheduledPublishTime schedules the reel instead of publishing it now
+	ScheduledPublishTime time.Time
+
+	// Retry controls retries of the binary upload; defaults to
+	// DefaultChunkRetryPolicy
+	Retry *RetryPolicy
+	// Progress is called as video bytes are sent
+	Progress UploadProgressFunc
+}
+
+// PublishResponse represents a published reel or story
+type PublishResponse struct {
+	ID      string `json:"id"`
+	PostID  string `json:"post_id,omitempty"`
+	Success bool   `json:"success"`
+}
+
+// videoPublishSession is returned by the start phase of reels and video stories
+type videoPublishSession struct {
+	VideoID   string `json:"video_id"`
+	UploadURL string `json:"upload_url"`
+}
+
+// PublishReelFromFile publishes a video file as a reel
+func (c *Client) PublishReelFromFile(ctx context.Context, pageID string, videoPath string, opts *ReelOptions) (*PublishResponse, error) {
+	file, err := os.Open(videoPath)
+	if err != nil {
+		return nil, fmt.Errorf("opening video file: %w", err)
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("reading video file info: %w", err)
+	}
+
+	return c.PublishReel(ctx, pageID, file, stat.Size(), opts)
+}
+
+// PublishReel initializes a reel, uploads the video and publishes, schedules
+// or drafts it according to opts. Use WaitForVideoReady with the returned ID
+// to follow processing.
+func (c *Client) PublishReel(ctx context.Context, pageID string, reader io.ReaderAt, size int64, opts *ReelOptions) (*PublishResponse, error) {
+	if opts == nil {
+		opts = &ReelOptions{}
+	}
+
+	session, err := c.startVideoPublish(ctx, pageID, "video_reels")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.uploadHostedVideo(ctx, session, reader, size, opts.Retry, opts.Progress); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("video_id", session.VideoID)
+
+	switch {
+	case !opts.ScheduledPublishTime.IsZero():
+		params.Set("video_state", VideoStateScheduled)
+		params.Set("scheduled_publish_time", strconv.FormatInt(opts.ScheduledPublishTime.Unix(), 10))
+	case opts.Draft:
+		params.Set("video_state", VideoStateDraft)
+	default:
+		params.Set("video_state", VideoStatePublished)
+	}
+
+	if opts.Description != "" {
+		params.Set("description", opts.Description)
+	}
+	if opts.Title != "" {
+		params.Set("title", opts.Title)
+	}
+
+	return c.finishVideoPublish(ctx, pageID, "video_reels", session, params)
+}
+
+// startVideoPublish runs the start phase on a reels or stories edge
+func (c *Client) startVideoPublish(ctx context.Context, pageID, edge string) (*videoPublishSession, error) {
+	params := url.Values{}
+	params.Set("upload_phase", "start")
+
+	endpoint := fmt.Sprintf("%s/%s", pageID, edge)
+	resp, err := c.makeRequestContext(ctx, "POST", endpoint, params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("initializing %s upload: %w", edge, err)
+	}
+
+	var session videoPublishSession
+	if err := c.handleResponse(resp, &session); err != nil {
+		return nil, err
+	}
+
+	if session.VideoID == "" || session.UploadURL == "" {
+		return nil, fmt.Errorf("initializing %s upload: missing video_id or upload_url", edge)
+	}
+
+	return &session, nil
+}
+
+// finishVideoPublish runs the finish phase on a reels or stories edge
+func (c *Client) finishVideoPublish(ctx context.Context, pageID, edge string, session *videoPublishSession, params url.Values) (*PublishResponse, error) {
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("upload_phase", "finish")
+	params.Set("video_id", session.VideoID)
+
+	endpoint := fmt.Sprintf("%s/%s", pageID, edge)
+	resp, err := c.makeRequestContext(ctx, "POST", endpoint, params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("publishing %s: %w", edge, err)
+	}
+
+	var publishResp PublishResponse
+	if err := c.handleResponse(resp, &publishResp); err != nil {
+		return nil, err
+	}
+
+	if !publishResp.Success {
+		return nil, fmt.Errorf("failed to publish %s", edge)
+	}
+
+	publishResp.ID = session.VideoID
+	return &publishResp, nil
+}
+
+// uploadHostedVideo sends the video binary to the upload URL returned by the
+// start phase. After a failed attempt the upload resumes from the bytes
+// Facebook reports as transferred.
+func (c *Client) uploadHostedVideo(ctx context.Context, session *videoPublishSession, reader io.ReaderAt, size int64, retry *RetryPolicy, progress UploadProgressFunc) error {
+	if size <= 0 {
+		return fmt.Errorf("video size must be positive")
+	}
+
+	policy := DefaultChunkRetryPolicy
+	if retry != nil {
+		policy = *retry
+	}
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+
+	var offset int64
+	var err error
+	for attempt := 1; attempt <= policy.MaxAttempts; attempt++ {
+		if err = c.uploadHostedVideoFrom(ctx, session.UploadURL, reader, offset, size, progress); err == nil {
+			return nil
+		}
+		if attempt == policy.MaxAttempts || ctx.Err() != nil {
+			break
+		}
+
+		c.recordRetry("hosted_video_upload")
+		select {
+		case <-ctx.Done():
+		case <-time.After(policy.backoff(attempt)):
+		}
+
+		if status, statusErr := c.GetVideoStatus(ctx, session.VideoID); statusErr == nil {
+			offset = status.UploadingPhase.BytesTransferred
+		}
+	}
+
+	return fmt.Errorf("uploading video: %w", err)
+}
+
+// uploadHostedVideoFrom sends the video bytes starting at offset. The
+// request is bounded by ctx only, as the body may be up to 1GB.
+func (c *Client) uploadHostedVideoFrom(ctx context.Context, uploadURL string, reader io.ReaderAt, offset, size int64, progress UploadProgressFunc) error {
+	var chunkProgress UploadProgressFunc
+	if progress != nil {
+		chunkProgress = func(sent, _ int64) {
+			progress(offset+sent, size)
+		}
+	}
+
+	body := &progressReader{
+		ctx:      ctx,
+		reader:   io.NewSectionReader(reader, offset, size-offset),
+		total:    size - offset,
+		progress: chunkProgress,
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", uploadURL, body)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.ContentLength = size - offset
+	req.Header.Set("Authorization", "OAuth "+c.AccessToken)
+	req.Header.Set("offset", strconv.FormatInt(offset, 10))
+	req.Header.Set("file_size", strconv.FormatInt(size, 10))
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := c.doTransfer(req)
+	if err != nil {
+		return fmt.Errorf("making upload request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading response body: %w", err)
+	}
+
+	var uploadResp struct {
+		Success   bool `json:"success"`
+		DebugInfo *struct {
+			Retriable bool   `json:"retriable"`
+			Type      string `json:"type"`
+			Message   string `json:"message"`
+		} `json:"debug_info"`
+	}
+
+	if err := json.Unmarshal(respBody, &uploadResp); err != nil {
+		return fmt.Errorf("upload error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	if uploadResp.DebugInfo != nil {
+		return fmt.Errorf("upload error: %s (%s)", uploadResp.DebugInfo.Message, uploadResp.DebugInfo.Type)
+	}
+
+	if resp.StatusCode >= 400 || !uploadResp.Success {
+		return fmt.Errorf("upload error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
@@ -0,0 +1,432 @@
+package facebook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Comparison periods. Week and days_28 compare the window ending at until
+// with the one before it; month compares calendar months; custom compares
+// since..until with the equally long window before it.
+const (
+	ComparePeriodWeek   = PeriodWeek
+	ComparePeriodDays28 = PeriodDays28
+	ComparePeriodMonth  = PeriodMonth
+	ComparePeriodCustom = "custom"
+)
+
+// validComparePeriods lists the periods accepted by comparison reports
+var validComparePeriods = []string{ComparePeriodWeek, ComparePeriodDays28, ComparePeriodMonth, ComparePeriodCustom}
+
+// Report output formats
+const (
+	ReportJSON     = "json"
+	ReportMarkdown = "markdown"
+	ReportHTML     = "html"
+)
+
+// validReportFormats lists the formats accepted by comparison reports
+var validReportFormats = []string{ReportJSON, ReportMarkdown, ReportHTML}
+
+// DefaultSignificanceThreshold is the relative change at which a metric is
+// flagged as significant
+const DefaultSignificanceThreshold = 0.10
+
+// ComparisonOptions configures ComparePeriods
+type ComparisonOptions struct {
+	// Period is week (default), days_28, month or custom
+	Period string
+	// Metrics default to the metrics of GetPageInsights
+	Metrics []string
+	// Since starts the current window of a custom comparison
+	Since time.Time
+	// Until ends the current window (exclusive); defaults to the start of
+	// today in UTC, so partial days are not compared
+	Until time.Time
+	// Threshold is the relative change flagged as significant (default 10%)
+	Threshold float64
+}
+
+// ReportWindow is a half-open time range [Since, Until)
+type ReportWindow struct {
+	Since time.Time `json:"since"`
+	Until time.Time `json:"until"`
+}
+
+// String formats the window as inclusive dates
+func (w ReportWindow) String() string {
+	return w.Since.Format("2006-01-02") + " – " + w.Until.Add(-time.Second).Format("2006-01-02")
+}
+
+// MetricComparison is the change of one metric between two windows
+type MetricComparison struct {
+	Metric   string  `json:"metric"`
+	Title    string  `json:"title,omitempty"`
+	Current  float64 `json:"current"`
+	Previous float64 `json:"previous"`
+	Delta    float64 `json:"delta"`
+	// PercentChange is nil when the previous value is zero
+	PercentChange *float64 `json:"percent_change"`
+	// Significant is set when the change reaches the report's threshold
+	Significant bool `json:"significant"`
+}
+
+// Direction describes the change as up, down or flat
+func (m MetricComparison) Direction() string {
+	switch {
+	case m.Delta > 0:
+		return "up"
+	case m.Delta < 0:
+		return "down"
+	}
+	return "flat"
+}
+
+// ComparisonReport compares page insights of two aligned windows
+type ComparisonReport struct {
+	PageID      string             `json:"page_id"`
+	Period      string             `json:"period"`
+	Current     ReportWindow       `json:"current"`
+	Previous    ReportWindow       `json:"previous"`
+	Threshold   float64            `json:"threshold"`
+	GeneratedAt time.Time          `json:"generated_at"`
+	Metrics     []MetricComparison `json:"metrics"`
+}
+
+// ParseComparisonOptions parses the period, metric, since, until, threshold
+// and format parameters of a comparison report request
+func ParseComparisonOptions(query url.Values) (*ComparisonOptions, string, error) {
+	opts := &ComparisonOptions{Period: ComparePeriodWeek}
+
+	if period := query.Get("period"); period != "" {
+		if !containsString(validComparePeriods, period) {
+			return nil, "", &InsightsQueryError{
+				Param:  "period",
+				Value:  period,
+				Reason: "must be one of " + strings.Join(validComparePeriods, ", "),
+			}
+		}
+		opts.Period = period
+	}
+
+	for _, metric := range strings.Split(query.Get("metric"), ",") {
+		if metric = strings.TrimSpace(metric); metric != "" {
+			opts.Metrics = append(opts.Metrics, metric)
+		}
+	}
+
+	since, err := parseInsightsDate("since", query.Get("since"))
+	if err != nil {
+		return nil, "", err
+	}
+	until, err := parseInsightsDate("until", query.Get("until"))
+	if err != nil {
+		return nil, "", err
+	}
+	if until != nil {
+		opts.Until = *until
+	}
+	if opts.Period == ComparePeriodCustom {
+		if since == nil {
+			return nil, "", &InsightsQueryError{Param: "since", Value: "", Reason: "required for custom comparisons"}
+		}
+		opts.Since = *since
+	}
+
+	if value := query.Get("threshold"); value != "" {
+		threshold, err := strconv.ParseFloat(value, 64)
+		if err != nil || threshold <= 0 {
+			return nil, "", &InsightsQueryError{Param: "threshold", Value: value, Reason: "must be a positive fraction, e.g. 0.1"}
+		}
+		opts.Threshold = threshold
+	}
+
+	format := ReportJSON
+	if value := query.Get("format"); value != "" {
+		if value == "md" {
+			value = ReportMarkdown
+		}
+		if !containsString(validReportFormats, value) {
+			return nil, "", &InsightsQueryError{
+				Param:  "format",
+				Value:  value,
+				Reason: "must be one of " + strings.Join(validReportFormats, ", "),
+			}
+		}
+		format = value
+	}
+
+	// Reject empty or inverted custom windows before any Graph call
+	if _, _, err := comparisonWindows(opts); err != nil {
+		return nil, "", err
+	}
+
+	return opts, format, nil
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// comparisonWindows returns the current and previous windows of a
+// comparison. Invalid options are reported as an *InsightsQueryError.
+func comparisonWindows(opts *ComparisonOptions) (ReportWindow, ReportWindow, error) {
+	until := opts.Until
+	if until.IsZero() {
+		until = time.Now().UTC().Truncate(24 * time.Hour)
+	}
+
+	var since, previousSince time.Time
+	switch opts.Period {
+	case "", ComparePeriodWeek:
+		since = until.AddDate(0, 0, -7)
+		previousSince = since.AddDate(0, 0, -7)
+	case ComparePeriodDays28:
+		since = until.AddDate(0, 0, -28)
+		previousSince = since.AddDate(0, 0, -28)
+	case ComparePeriodMonth:
+		// Compare the last complete calendar month with the one before it
+		until = time.Date(until.Year(), until.Month(), 1, 0, 0, 0, 0, until.Location())
+		since = until.AddDate(0, -1, 0)
+		previousSince = since.AddDate(0, -1, 0)
+	case ComparePeriodCustom:
+		since = opts.Since
+		if !until.After(since) {
+			return ReportWindow{}, ReportWindow{}, &InsightsQueryError{
+				Param:  "until",
+				Value:  until.Format("2006-01-02"),
+				Reason: "must be after since",
+			}
+		}
+		previousSince = since.Add(-until.Sub(since))
+	default:
+		return ReportWindow{}, ReportWindow{}, &InsightsQueryError{
+			Param:  "period",
+			Value:  opts.Period,
+			Reason: "must be one of " + strings.Join(validComparePeriods, ", "),
+		}
+	}
+
+	return ReportWindow{Since: since, Until: until}, ReportWindow{Since: previousSince, Until: since}, nil
+}
+
+// ComparePeriods fetches daily page insights for two aligned windows and
+// compares their totals. Daily values are summed, so unique-reach metrics
+// overcount people reached on several days; compare them as trends.
+func (c *Client) ComparePeriods(ctx context.Context, pageID string, opts *ComparisonOptions) (*ComparisonReport, error) {
+	if opts == nil {
+		opts = &ComparisonOptions{}
+	}
+	current, previous, err := comparisonWindows(opts)
+	if err != nil {
+		return nil, err
+	}
+	threshold := opts.Threshold
+	if threshold <= 0 {
+		threshold = DefaultSignificanceThreshold
+	}
+	period := opts.Period
+	if period == "" {
+		period = ComparePeriodWeek
+	}
+
+	metrics, _ := pageInsightsDefaults(opts.Metrics, PeriodDay)
+
+	currentInsights, err := c.GetPageInsightsRange(ctx, pageID, metrics, PeriodDay, current.Since, current.Until, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting current window: %w", err)
+	}
+	previousInsights, err := c.GetPageInsightsRange(ctx, pageID, metrics, PeriodDay, previous.Since, previous.Until, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting previous window: %w", err)
+	}
+
+	return &ComparisonReport{
+		PageID:      pageID,
+		Period:      period,
+		Current:     current,
+		Previous:    previous,
+		Threshold:   threshold,
+		GeneratedAt: time.Now().UTC(),
+		Metrics:     CompareInsights(metrics, currentInsights, previousInsights, threshold),
+	}, nil
+}
+
+// CompareInsights compares the summed values of each metric in two insights
+// responses, in the order of metrics
+func CompareInsights(metrics []string, current, previous *InsightsResponse, threshold float64) []MetricComparison {
+	currentTotals, titles := insightTotals(current)
+	previousTotals, _ := insightTotals(previous)
+
+	comparisons := make([]MetricComparison, 0, len(metrics))
+	for _, metric := range metrics {
+		comparison := MetricComparison{
+			Metric:   metric,
+			Title:    titles[metric],
+			Current:  currentTotals[metric],
+			Previous: previousTotals[metric],
+		}
+		if comparison.Title == "" {
+			if definition, ok := LookupMetric(metric); ok {
+				comparison.Title = definition.Description
+			}
+		}
+
+		comparison.Delta = comparison.Current - comparison.Previous
+		if comparison.Previous != 0 {
+			change := comparison.Delta / math.Abs(comparison.Previous)
+			comparison.PercentChange = &change
+			comparison.Significant = math.Abs(change) >= threshold
+		} else {
+			comparison.Significant = comparison.Current != 0
+		}
+
+		comparisons = append(comparisons, comparison)
+	}
+	return comparisons
+}
+
+// insightTotals sums the values of each metric and collects their titles
+func insightTotals(insights *InsightsResponse) (map[string]float64, map[string]string) {
+	totals := make(map[string]float64)
+	titles := make(map[string]string)
+	for _, insight := range insights.Data {
+		for _, value := range insight.Values {
+			totals[insight.Name] += value.Float()
+		}
+		if insight.Title != "" {
+			titles[insight.Name] = insight.Title
+		}
+	}
+	return totals, titles
+}
+
+// WriteComparisonReport writes a report as JSON, Markdown or HTML
+func WriteComparisonReport(w io.Writer, format string, report *ComparisonReport) error {
+	switch format {
+	case "", ReportJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	case ReportMarkdown:
+		return WriteComparisonMarkdown(w, report)
+	case ReportHTML:
+		return WriteComparisonHTML(w, report)
+	}
+	return fmt.Errorf("unknown report format %q", format)
+}
+
+// ReportContentType returns the Content-Type of a report format
+func ReportContentType(format string) string {
+	switch format {
+	case ReportMarkdown:
+		return "text/markdown; charset=utf-8"
+	case ReportHTML:
+		return "text/html; charset=utf-8"
+	}
+	return "application/json"
+}
+
+// WriteComparisonMarkdown writes a report as a Markdown table
+func WriteComparisonMarkdown(w io.Writer, report *ComparisonReport) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Page %s: %s comparison\n\n", report.PageID, report.Period)
+	fmt.Fprintf(&b, "Current: %s  \nPrevious: %s\n\n", report.Current, report.Previous)
+	b.WriteString("| Metric | Current | Previous | Change | % |\n")
+	b.WriteString("|---|---:|---:|---:|---:|\n")
+	for _, m := range report.Metrics {
+		metric := m.Metric
+		if m.Significant {
+			metric = "**" + metric + "**"
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n",
+			metric, formatReportNumber(m.Current), formatReportNumber(m.Previous), formatReportDelta(m.Delta), formatPercentChange(m.PercentChange))
+	}
+	fmt.Fprintf(&b, "\nMetrics in bold changed by at least %s.\n", formatPercent(report.Threshold))
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// comparisonHTML renders a report as a standalone HTML page
+var comparisonHTML = template.Must(template.New("comparison").Funcs(template.FuncMap{
+	"number":  formatReportNumber,
+	"delta":   formatReportDelta,
+	"percent": formatPercentChange,
+	"pct":     formatPercent,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Page {{.PageID}}: {{.Period}} comparison</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { padding: 0.3em 0.8em; border-bottom: 1px solid #ddd; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+.significant { font-weight: bold; }
+.up { color: #1a7f37; }
+.down { color: #cf222e; }
+</style>
+</head>
+<body>
+<h1>Page {{.PageID}}: {{.Period}} comparison</h1>
+<p>Current: {{.Current}}<br>Previous: {{.Previous}}</p>
+<table>
+<tr><th>Metric</th><th>Current</th><th>Previous</th><th>Change</th><th>%</th></tr>
+{{- range .Metrics}}
+<tr class="{{.Direction}}{{if .Significant}} significant{{end}}"><td title="{{.Title}}">{{.Metric}}</td><td>{{number .Current}}</td><td>{{number .Previous}}</td><td>{{delta .Delta}}</td><td>{{percent .PercentChange}}</td></tr>
+{{- end}}
+</table>
+<p>Metrics in bold changed by at least {{pct .Threshold}}.</p>
+</body>
+</html>
+`))
+
+// WriteComparisonHTML writes a report as a standalone HTML page
+func WriteComparisonHTML(w io.Writer, report *ComparisonReport) error {
+	return comparisonHTML.Execute(w, report)
+}
+
+// formatReportNumber formats a value without trailing zeros
+func formatReportNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// formatReportDelta formats a change with an explicit sign
+func formatReportDelta(delta float64) string {
+	if delta > 0 {
+		return "+" + formatReportNumber(delta)
+	}
+	return formatReportNumber(delta)
+}
+
+// formatPercentChange formats a relative change, or n/a without a baseline
+func formatPercentChange(change *float64) string {
+	if change == nil {
+		return "n/a"
+	}
+	if *change > 0 {
+		return "+" + formatPercent(*change)
+	}
+	return formatPercent(*change)
+}
+
+// formatPercent formats a fraction as a percentage with one decimal
+func formatPercent(fraction float64) string {
+	return strconv.FormatFloat(fraction*100, 'f', 1, 64) + "%"
+}
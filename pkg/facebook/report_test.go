@@ -0,0 +1,113 @@
+package facebook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestComparisonWindowsAlignMonths(t *testing.T) {
+	current, previous, err := comparisonWindows(&ComparisonOptions{
+		Period: ComparePeriodMonth,
+		Until:  time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Expected windows, got %v", err)
+	}
+
+	if current.String() != "2024-02-01 – 2024-02-29" {
+		t.Errorf("Unexpected current window %s", current)
+	}
+	if previous.String() != "2024-01-01 – 2024-01-31" {
+		t.Errorf("Unexpected previous window %s", previous)
+	}
+}
+
+func TestCompareInsightsFlagsSignificantChanges(t *testing.T) {
+	daily := func(name string, values ...float64) Insight {
+		insight := Insight{Name: name, Period: PeriodDay}
+		for _, v := range values {
+			insight.Values = append(insight.Values, Value{Value: v})
+		}
+		return insight
+	}
+
+	current := &InsightsResponse{Data: []Insight{
+		daily("page_impressions", 100, 120),
+		daily("page_views_total", 50),
+		daily("page_video_views", 3),
+	}}
+	previous := &InsightsResponse{Data: []Insight{
+		daily("page_impressions", 100, 100),
+		daily("page_views_total", 52),
+	}}
+
+	metrics := []string{"page_impressions", "page_views_total", "page_video_views"}
+	comparisons := CompareInsights(metrics, current, previous, 0.1)
+
+	impressions := comparisons[0]
+	if impressions.Delta != 20 || *impressions.PercentChange != 0.1 || !impressions.Significant {
+		t.Errorf("Expected +10%% significant impressions, got %+v", impressions)
+	}
+	if comparisons[1].Significant || comparisons[1].Direction() != "down" {
+		t.Errorf("Expected a small insignificant drop in views, got %+v", comparisons[1])
+	}
+	if comparisons[2].PercentChange != nil || !comparisons[2].Significant {
+		t.Errorf("Expected a new metric to be significant without a percentage, got %+v", comparisons[2])
+	}
+
+	report := &ComparisonReport{PageID: "page", Period: ComparePeriodWeek, Threshold: 0.1, Metrics: comparisons}
+	var out bytes.Buffer
+	if err := WriteComparisonReport(&out, ReportMarkdown, report); err != nil {
+		t.Fatalf("Expected Markdown output, got %v", err)
+	}
+	if !strings.Contains(out.String(), "| **page_impressions** | 220 | 200 | +20 | +10.0% |") {
+		t.Errorf("Unexpected Markdown report:\n%s", out.String())
+	}
+}
+
+func TestInvalidCustomComparisonIsBadRequest(t *testing.T) {
+	for _, query := range []string{"since=2024-03-10&until=2024-03-01", "since=2024-03-10&until=2024-03-10"} {
+		values, _ := url.ParseQuery("period=custom&" + query)
+		_, _, err := ParseComparisonOptions(values)
+		var queryErr *InsightsQueryError
+		if !errors.As(err, &queryErr) || queryErr.Param != "until" {
+			t.Errorf("Expected an until error for %s, got %v", query, err)
+		}
+	}
+
+	// ComparePeriods reports the same error to library callers
+	_, err := NewClient("token").ComparePeriods(context.Background(), "page", &ComparisonOptions{
+		Period: ComparePeriodCustom,
+		Since:  time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+		Until:  time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	})
+	var queryErr *InsightsQueryError
+	if !errors.As(err, &queryErr) {
+		t.Errorf("Expected an InsightsQueryError, got %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		t.Errorf("Unexpected request %s", req.URL)
+	}))
+	defer server.Close()
+
+	router := NewRouter("token")
+	router.defaultClient.BaseURL = server.URL
+	simple := NewSimpleRouter("token")
+	simple.defaultClient.BaseURL = server.URL
+
+	for name, handler := range map[string]http.Handler{"Router": router.SetupRoutes(), "SimpleRouter": simple} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", "/api/pages/page_1/reports/compare?period=custom&since=2024-03-10&until=2024-03-01", nil))
+		if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), `"param":"until"`) {
+			t.Errorf("%s: expected 400 for an inverted window, got %d: %s", name, rec.Code, rec.Body.String())
+		}
+	}
+}
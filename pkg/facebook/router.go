@@ -2,10 +2,14 @@ package facebook
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 )
@@ -13,6 +17,17 @@ import (
 // Router handles HTTP routes for Facebook Pages API
 type Router struct {
 	defaultClient *Client
+	webhooks      *WebhookHandler
+	
+	messagingWindow *MessagingWindowTracker
+	
+	subscriptionAppID  string
+	subscriptionFields []string
+	adminToken         string
+	
+	insightStore *InsightStore
+	metrics      *MetricsRegistry
+	tracer       *Tracer
 }
 
 // NewRouter creates a new router with a default Facebook client
@@ -22,8 +37,57 @@ func NewRouter(defaultAccessToken string) *Router {
 		client = NewClient(defaultAccessToken)
 	}
 	return &Router{
-		defaultClient: client,
+		defaultClient:   client,
+		messagingWindow: NewMessagingWindowTracker(),
+	}
+}
+
+// instrument is middleware recording the count and latency of requests by
+// route template
+func (r *Router) instrument(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		route := "unmatched"
+		if current := mux.CurrentRoute(req); current != nil {
+			if template, err := current.GetPathTemplate(); err == nil {
+				route = template
+			}
+		}
+		
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, req)
+		r.metrics.observeHTTPRequest(route, req.Method, recorder.status, time.Since(start))
+	})
+}
+
+// unmatched applies the enabled middleware to a fallback handler, in the
+// order router.Use applies it to routes
+func (r *Router) unmatched(handler http.Handler) http.Handler {
+	if r.tracer != nil {
+		handler = r.trace(handler)
 	}
+	if r.metrics != nil {
+		handler = r.instrument(handler)
+	}
+	return handler
+}
+
+// trace is middleware running each request in a server span named after
+// its route template
+func (r *Router) trace(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		route := "unmatched"
+		if current := mux.CurrentRoute(req); current != nil {
+			if template, err := current.GetPathTemplate(); err == nil {
+				route = template
+			}
+		}
+		
+		req, span := startServerSpan(r.tracer, req)
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, req)
+		endServerSpan(span, req.Method, route, recorder.status)
+	})
 }
 
 // getClientFromRequest creates a client from request parameters or uses default
@@ -44,11 +108,71 @@ func (r *Router) getClientFromRequest(req *http.Request) (*Client, error) {
 		if r.defaultClient == nil {
 			return nil, fmt.Errorf("no access token provided and no default token configured")
 		}
-		return r.defaultClient, nil
+		return r.defaultClient.WithContext(req.Context()), nil
 	}
 	
 	// Create new client with provided token
-	return NewClient(accessToken), nil
+	return r.newClient(accessToken).WithContext(req.Context()), nil
+}
+
+// MessagingWindow returns the tracker used to enforce the Messenger
+// messaging window on POST /api/pages/{pageId}/messages
+func (r *Router) MessagingWindow() *MessagingWindowTracker {
+	return r.messagingWindow
+}
+
+// SetWebhookHandler enables the /webhooks/facebook endpoint
+func (r *Router) SetWebhookHandler(handler *WebhookHandler) {
+	r.webhooks = handler
+}
+
+// SetDesiredSubscriptions enables the /admin/subscriptions endpoints, which
+// reconcile appID's webhook subscription on every managed page to fields.
+// Callers must send adminToken in the X-Admin-Token header and their own user
+// access token; the endpoints stay disabled without an admin token.
+func (r *Router) SetDesiredSubscriptions(appID string, fields []string, adminToken string) {
+	r.subscriptionAppID = appID
+	r.subscriptionFields = fields
+	r.adminToken = adminToken
+}
+
+// SetMetricsRegistry enables the /metrics endpoint, which serves registry in
+// the Prometheus text format, and records request, Graph call and cache
+// metrics into it
+func (r *Router) SetMetricsRegistry(registry *MetricsRegistry) {
+	r.metrics = registry
+	r.messagingWindow.Metrics = registry
+	if r.defaultClient != nil {
+		r.defaultClient.Metrics = registry
+	}
+}
+
+// SetTracer starts a server span for every request and a child span for
+// every Graph call made while serving it
+func (r *Router) SetTracer(tracer *Tracer) {
+	r.tracer = tracer
+	if r.defaultClient != nil {
+		r.defaultClient.Tracer = tracer
+	}
+}
+
+// newClient creates a client for a request token that reports to the
+// router's metrics registry and tracer
+func (r *Router) newClient(accessToken string) *Client {
+	// Keep the API version and endpoints configured on the default client
+	client := NewClient(accessToken)
+	if r.defaultClient != nil {
+		client = r.defaultClient.WithAccessToken(accessToken)
+	}
+	client.Metrics = r.metrics
+	client.Tracer = r.tracer
+	return client
+}
+
+// SetInsightStore enables the insights history endpoints, which serve points
+// collected into store
+func (r *Router) SetInsightStore(store *InsightStore) {
+	r.insightStore = store
 }
 
 // SetupRoutes configures all the API routes
@@ -67,6 +191,71 @@ func (r *Router) SetupRoutes() *mux.Router {
 	router.HandleFunc("/api/comments/{commentId}", r.getComment).Methods("GET")
 	router.HandleFunc("/api/comments/{commentId}/replies", r.getCommentReplies).Methods("GET")
 	
+	// Messenger routes
+	router.HandleFunc("/api/pages/{pageId}/conversations", r.getConversations).Methods("GET")
+	router.HandleFunc("/api/pages/{pageId}/messages", r.sendMessage).Methods("POST")
+	router.HandleFunc("/api/conversations/{conversationId}/messages", r.getConversationMessages).Methods("GET")
+	
+	// Insights routes
+	router.HandleFunc("/api/pages/{pageId}/insights", r.getPageInsights).Methods("GET")
+	router.HandleFunc("/api/pages/{pageId}/insights/metrics", r.getAvailableMetrics).Methods("GET")
+	router.HandleFunc("/api/posts/{postId}/insights", r.getPostInsights).Methods("GET")
+	router.HandleFunc("/api/pages/{pageId}/analytics", r.getPageAnalytics).Methods("GET")
+	router.HandleFunc("/api/pages/{pageId}/reports/compare", r.comparePeriods).Methods("GET")
+	
+	// Photo routes
+	router.HandleFunc("/api/pages/{pageId}/photos", r.getPhotos).Methods("GET")
+	router.HandleFunc("/api/pages/{pageId}/photos", r.uploadPhoto).Methods("POST")
+	router.HandleFunc("/api/photos/{photoId}", r.deletePhoto).Methods("DELETE")
+	
+	// Album routes
+	router.HandleFunc("/api/pages/{pageId}/albums", r.getAlbums).Methods("GET")
+	router.HandleFunc("/api/pages/{pageId}/albums", r.createAlbum).Methods("POST")
+	router.HandleFunc("/api/albums/{albumId}/photos", r.getAlbumPhotos).Methods("GET")
+	router.HandleFunc("/api/albums/{albumId}/photos", r.uploadAlbumPhoto).Methods("POST")
+	
+	// Reels and Stories routes
+	router.HandleFunc("/api/pages/{pageId}/reels", r.publishReel).Methods("POST")
+	router.HandleFunc("/api/pages/{pageId}/stories", r.publishStory).Methods("POST")
+	router.HandleFunc("/api/videos/{videoId}/status", r.getVideoStatus).Methods("GET")
+	
+	// Insights history
+	if r.insightStore != nil {
+		router.HandleFunc("/api/pages/{pageId}/insights/history", r.getInsightsHistory).Methods("GET")
+		router.HandleFunc("/api/posts/{postId}/insights/history", r.getInsightsHistory).Methods("GET")
+	}
+	
+	// Prometheus metrics and request instrumentation
+	if r.metrics != nil {
+		router.Handle("/metrics", r.metrics).Methods("GET")
+		router.Use(r.instrument)
+	}
+	
+	// Request tracing
+	if r.tracer != nil {
+		router.Use(r.trace)
+	}
+	
+	// mux runs middleware only for matched routes, so 404 and 405 responses
+	// are wrapped here to be recorded under the "unmatched" route
+	if r.metrics != nil || r.tracer != nil {
+		router.NotFoundHandler = r.unmatched(http.NotFoundHandler())
+		router.MethodNotAllowedHandler = r.unmatched(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}))
+	}
+	
+	// Webhook receiver
+	if r.webhooks != nil {
+		router.Handle("/webhooks/facebook", r.webhooks).Methods("GET", "POST")
+	}
+	
+	// Webhook subscription admin
+	if r.subscriptionAppID != "" && r.adminToken != "" {
+		router.HandleFunc("/admin/subscriptions", r.reconcileSubscriptions).Methods("GET")
+		router.HandleFunc("/admin/subscriptions/reconcile", r.reconcileSubscriptions).Methods("POST")
+	}
+	
 	// Health check
 	router.HandleFunc("/health", r.healthCheck).Methods("GET")
 	
@@ -291,34 +480,867 @@ func (r *Router) getCommentReplies(w http.ResponseWriter, req *http.Request) {
 	r.writeJSON(w, http.StatusOK, replies)
 }
 
-// healthCheck handles GET /health
-func (r *Router) healthCheck(w http.ResponseWriter, req *http.Request) {
-	version := "v23.0" // Default API version
-	if r.defaultClient != nil {
-		version = r.defaultClient.APIVersion
+// getConversations handles GET /api/pages/{pageId}/conversations
+func (r *Router) getConversations(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	pageID := vars["pageId"]
+	
+	if pageID == "" {
+		r.writeError(w, http.StatusBadRequest, "Page ID is required")
+		return
 	}
 	
-	r.writeJSON(w, http.StatusOK, map[string]interface{}{
-		"status":  "ok",
-		"service": "facebook-pages-api",
-		"version": version,
-	})
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	// Parse limit parameter
+	limitParam := req.URL.Query().Get("limit")
+	limit := 10 // default
+	if limitParam != "" {
+		if l, err := strconv.Atoi(limitParam); err == nil && l > 0 {
+			limit = l
+		}
+	}
+	
+	// Parse fields parameter
+	fieldsParam := req.URL.Query().Get("fields")
+	var fields []string
+	if fieldsParam != "" {
+		fields = strings.Split(fieldsParam, ",")
+	}
+	
+	conversations, err := client.GetConversations(pageID, limit, fields...)
+	if err != nil {
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error getting conversations: %v", err))
+		return
+	}
+	
+	r.writeJSON(w, http.StatusOK, conversations)
 }
 
-// writeJSON writes a JSON response
-func (r *Router) writeJSON(w http.ResponseWriter, statusCode int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
+// getConversationMessages handles GET /api/conversations/{conversationId}/messages
+func (r *Router) getConversationMessages(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	conversationID := vars["conversationId"]
 	
-	if err := json.NewEncoder(w).Encode(data); err != nil {
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	if conversationID == "" {
+		r.writeError(w, http.StatusBadRequest, "Conversation ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	// Parse limit parameter
+	limitParam := req.URL.Query().Get("limit")
+	limit := 25 // default
+	if limitParam != "" {
+		if l, err := strconv.Atoi(limitParam); err == nil && l > 0 {
+			limit = l
+		}
+	}
+	
+	// Parse fields parameter
+	fieldsParam := req.URL.Query().Get("fields")
+	var fields []string
+	if fieldsParam != "" {
+		fields = strings.Split(fieldsParam, ",")
+	}
+	
+	messages, err := client.GetConversationMessages(conversationID, limit, fields...)
+	if err != nil {
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error getting messages: %v", err))
+		return
 	}
+	
+	r.writeJSON(w, http.StatusOK, messages)
 }
 
-// writeError writes an error response
-func (r *Router) writeError(w http.ResponseWriter, statusCode int, message string) {
-	r.writeJSON(w, statusCode, map[string]interface{}{
-		"error": message,
-		"code":  statusCode,
+// sendMessage handles POST /api/pages/{pageId}/messages
+func (r *Router) sendMessage(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	pageID := vars["pageId"]
+	
+	if pageID == "" {
+		r.writeError(w, http.StatusBadRequest, "Page ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	var message SendMessageRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, 1<<20)).Decode(&message); err != nil {
+		r.writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid message body: %v", err))
+		return
+	}
+	
+	if err := message.Validate(); err != nil {
+		r.writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid message: %v", err))
+		return
+	}
+	
+	result, err := client.SendMessageWithinWindow(pageID, &message, r.messagingWindow)
+	if err != nil {
+		var policyErr *MessagingPolicyError
+		if errors.As(err, &policyErr) {
+			r.writeJSON(w, http.StatusForbidden, map[string]interface{}{
+				"error":  policyErr.Error(),
+				"code":   http.StatusForbidden,
+				"policy": policyErr,
+			})
+			return
+		}
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error sending message: %v", err))
+		return
+	}
+	
+	r.writeJSON(w, http.StatusOK, result)
+}
+
+// getPageInsights handles GET /api/pages/{pageId}/insights
+func (r *Router) getPageInsights(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	pageID := vars["pageId"]
+	
+	if pageID == "" {
+		r.writeError(w, http.StatusBadRequest, "Page ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	query, err := ParseInsightsQuery(req.URL.Query())
+	if err != nil {
+		r.writeQueryError(w, err)
+		return
+	}
+	
+	insights, err := client.GetPageInsights(pageID, query.Metrics, query.Period, query.Since, query.Until)
+	if err != nil {
+		if errors.Is(err, ErrInvalidMetric) {
+			r.writeQueryError(w, err)
+			return
+		}
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error getting page insights: %v", err))
+		return
+	}
+	
+	r.writeInsights(w, query.Format, MetricObjectPage, pageID, insights)
+}
+
+// getPostInsights handles GET /api/posts/{postId}/insights
+func (r *Router) getPostInsights(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	postID := vars["postId"]
+	
+	if postID == "" {
+		r.writeError(w, http.StatusBadRequest, "Post ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	query, err := ParseInsightsQuery(req.URL.Query())
+	if err != nil {
+		r.writeQueryError(w, err)
+		return
+	}
+	
+	// Post metrics are only reported over the lifetime of the post
+	if query.Period != "" && query.Period != PeriodLifetime {
+		r.writeQueryError(w, &InsightsQueryError{
+			Param:  "period",
+			Value:  query.Period,
+			Reason: "post insights only support lifetime",
+		})
+		return
+	}
+	if query.Since != nil || query.Until != nil {
+		r.writeQueryError(w, &InsightsQueryError{
+			Param:  "since",
+			Value:  req.URL.Query().Get("since"),
+			Reason: "post insights do not support a date range",
+		})
+		return
+	}
+	
+	insights, err := client.GetPostInsights(postID, query.Metrics)
+	if err != nil {
+		if errors.Is(err, ErrInvalidMetric) {
+			r.writeQueryError(w, err)
+			return
+		}
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error getting post insights: %v", err))
+		return
+	}
+	
+	r.writeInsights(w, query.Format, MetricObjectPost, postID, insights)
+}
+
+// getAvailableMetrics handles GET /api/pages/{pageId}/insights/metrics
+func (r *Router) getAvailableMetrics(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	pageID := vars["pageId"]
+	
+	if pageID == "" {
+		r.writeError(w, http.StatusBadRequest, "Page ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	metrics, err := client.GetAvailableMetrics(pageID)
+	if err != nil {
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error getting available metrics: %v", err))
+		return
+	}
+	
+	r.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data":        metrics,
+		"api_version": client.APIVersion,
 	})
 }
+	
+// getInsightsHistory handles GET /api/pages/{pageId}/insights/history and
+// GET /api/posts/{postId}/insights/history
+func (r *Router) getInsightsHistory(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	object, objectID := MetricObjectPage, vars["pageId"]
+	if postID := vars["postId"]; postID != "" {
+		object, objectID = MetricObjectPost, postID
+	}
+	
+	query, err := ParseInsightsQuery(req.URL.Query())
+	if err != nil {
+		r.writeQueryError(w, err)
+		return
+	}
+	
+	points, err := r.insightStore.Query(query.storeQuery(objectID))
+	if err != nil {
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error reading insights history: %v", err))
+		return
+	}
+	
+	r.writeInsights(w, query.Format, object, objectID, PointsToInsights(points))
+}
+	
+// getPageAnalytics handles GET /api/pages/{pageId}/analytics
+func (r *Router) getPageAnalytics(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	pageID := vars["pageId"]
+	
+	if pageID == "" {
+		r.writeError(w, http.StatusBadRequest, "Page ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	opts, err := ParseAnalyticsOptions(req.URL.Query())
+	if err != nil {
+		r.writeQueryError(w, err)
+		return
+	}
+	
+	analytics, err := client.GetPageAnalytics(req.Context(), pageID, opts)
+	if err != nil {
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error getting page analytics: %v", err))
+		return
+	}
+	
+	r.writeJSON(w, http.StatusOK, analytics)
+}
+	
+// comparePeriods handles GET /api/pages/{pageId}/reports/compare
+func (r *Router) comparePeriods(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	pageID := vars["pageId"]
+	
+	if pageID == "" {
+		r.writeError(w, http.StatusBadRequest, "Page ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	opts, format, err := ParseComparisonOptions(req.URL.Query())
+	if err != nil {
+		r.writeQueryError(w, err)
+		return
+	}
+	
+	report, err := client.ComparePeriods(req.Context(), pageID, opts)
+	if err != nil {
+		if errors.Is(err, ErrInvalidMetric) {
+			r.writeQueryError(w, err)
+			return
+		}
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error comparing periods: %v", err))
+		return
+	}
+	
+	if format == ReportJSON {
+		r.writeJSON(w, http.StatusOK, report)
+		return
+	}
+	
+	w.Header().Set("Content-Type", ReportContentType(format))
+	if err := WriteComparisonReport(w, format, report); err != nil {
+		log.Printf("Error writing %s report for %s: %v", format, pageID, err)
+	}
+}
+	
+// getPhotos handles GET /api/pages/{pageId}/photos
+func (r *Router) getPhotos(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	pageID := vars["pageId"]
+	
+	if pageID == "" {
+		r.writeError(w, http.StatusBadRequest, "Page ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	// Parse limit parameter
+	limitParam := req.URL.Query().Get("limit")
+	limit := 25 // default
+	if limitParam != "" {
+		if l, err := strconv.Atoi(limitParam); err == nil && l > 0 {
+			limit = l
+		}
+	}
+	
+	photos, err := client.GetPhotos(pageID, limit)
+	if err != nil {
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error getting photos: %v", err))
+		return
+	}
+	
+	r.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data": photos,
+	})
+}
+
+// uploadPhoto handles POST /api/pages/{pageId}/photos with a multipart
+// file or a JSON body with a url
+func (r *Router) uploadPhoto(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	pageID := vars["pageId"]
+	
+	if pageID == "" {
+		r.writeError(w, http.StatusBadRequest, "Page ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	upload, err := readPhotoUpload(w, req, DefaultPhotoLimits.MaxBytes)
+	if err != nil {
+		r.writeError(w, uploadErrorStatus(err), fmt.Sprintf("Invalid upload: %v", err))
+		return
+	}
+	
+	var photo *PhotoResponse
+	if upload.URL != "" {
+		photo, err = client.UploadPhotoByURL(pageID, upload.URL, upload.Message, upload.published())
+	} else {
+		photo, err = client.UploadPhotoFromReaderContext(req.Context(), pageID, upload.file, upload.Message, upload.published(), nil)
+	}
+	
+	if err != nil {
+		var mediaErr *MediaValidationError
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &mediaErr) || errors.As(err, &maxBytesErr) {
+			r.writeError(w, uploadErrorStatus(err), fmt.Sprintf("Invalid media: %v", err))
+			return
+		}
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error uploading photo: %v", err))
+		return
+	}
+	
+	r.writeJSON(w, http.StatusCreated, photo)
+}
+
+// deletePhoto handles DELETE /api/photos/{photoId}
+func (r *Router) deletePhoto(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	photoID := vars["photoId"]
+	
+	if photoID == "" {
+		r.writeError(w, http.StatusBadRequest, "Photo ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	if err := client.DeletePhoto(photoID); err != nil {
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error deleting photo: %v", err))
+		return
+	}
+	
+	r.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+	})
+}
+
+// getAlbums handles GET /api/pages/{pageId}/albums
+func (r *Router) getAlbums(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	pageID := vars["pageId"]
+	
+	if pageID == "" {
+		r.writeError(w, http.StatusBadRequest, "Page ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	// Parse limit parameter
+	limitParam := req.URL.Query().Get("limit")
+	limit := 25 // default
+	if limitParam != "" {
+		if l, err := strconv.Atoi(limitParam); err == nil && l > 0 {
+			limit = l
+		}
+	}
+	
+	// Parse fields parameter
+	fieldsParam := req.URL.Query().Get("fields")
+	var fields []string
+	if fieldsParam != "" {
+		fields = strings.Split(fieldsParam, ",")
+	}
+	
+	albums, err := client.GetAlbums(pageID, limit, req.URL.Query().Get("after"), fields...)
+	if err != nil {
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error getting albums: %v", err))
+		return
+	}
+	
+	r.writeJSON(w, http.StatusOK, albums)
+}
+
+// createAlbum handles POST /api/pages/{pageId}/albums
+func (r *Router) createAlbum(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	pageID := vars["pageId"]
+	
+	if pageID == "" {
+		r.writeError(w, http.StatusBadRequest, "Page ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	var albumReq struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, 1<<20)).Decode(&albumReq); err != nil {
+		r.writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid album body: %v", err))
+		return
+	}
+	
+	if albumReq.Name == "" {
+		r.writeError(w, http.StatusBadRequest, "Album name is required")
+		return
+	}
+	
+	album, err := client.CreateAlbum(pageID, albumReq.Name, albumReq.Description)
+	if err != nil {
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error creating album: %v", err))
+		return
+	}
+	
+	r.writeJSON(w, http.StatusCreated, album)
+}
+
+// getAlbumPhotos handles GET /api/albums/{albumId}/photos
+func (r *Router) getAlbumPhotos(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	albumID := vars["albumId"]
+	
+	if albumID == "" {
+		r.writeError(w, http.StatusBadRequest, "Album ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	// Parse limit parameter
+	limitParam := req.URL.Query().Get("limit")
+	limit := 25 // default
+	if limitParam != "" {
+		if l, err := strconv.Atoi(limitParam); err == nil && l > 0 {
+			limit = l
+		}
+	}
+	
+	// Parse fields parameter
+	fieldsParam := req.URL.Query().Get("fields")
+	var fields []string
+	if fieldsParam != "" {
+		fields = strings.Split(fieldsParam, ",")
+	}
+	
+	photos, err := client.GetAlbumPhotos(albumID, limit, req.URL.Query().Get("after"), fields...)
+	if err != nil {
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error getting album photos: %v", err))
+		return
+	}
+	
+	r.writeJSON(w, http.StatusOK, photos)
+}
+
+// uploadAlbumPhoto handles POST /api/albums/{albumId}/photos with a
+// multipart file or a JSON body with a url, like uploadPhoto
+func (r *Router) uploadAlbumPhoto(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	albumID := vars["albumId"]
+	
+	if albumID == "" {
+		r.writeError(w, http.StatusBadRequest, "Album ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	upload, err := readPhotoUpload(w, req, DefaultPhotoLimits.MaxBytes)
+	if err != nil {
+		r.writeError(w, uploadErrorStatus(err), fmt.Sprintf("Invalid upload: %v", err))
+		return
+	}
+	
+	var photo *PhotoResponse
+	if upload.URL != "" {
+		photo, err = client.UploadPhotoByURL(albumID, upload.URL, upload.Message, true)
+	} else {
+		photo, err = client.UploadPhotoToAlbumFromReader(req.Context(), albumID, upload.file, upload.Message, nil)
+	}
+	
+	if err != nil {
+		var mediaErr *MediaValidationError
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &mediaErr) || errors.As(err, &maxBytesErr) {
+			r.writeError(w, uploadErrorStatus(err), fmt.Sprintf("Invalid media: %v", err))
+			return
+		}
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error uploading photo to album: %v", err))
+		return
+	}
+	
+	r.writeJSON(w, http.StatusCreated, photo)
+}
+
+// publishReel handles POST /api/pages/{pageId}/reels
+func (r *Router) publishReel(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	pageID := vars["pageId"]
+	
+	if pageID == "" {
+		r.writeError(w, http.StatusBadRequest, "Page ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	upload, err := receiveUpload(w, req, MaxVideoUploadBytes)
+	if err != nil {
+		r.writeError(w, uploadErrorStatus(err), fmt.Sprintf("Invalid upload: %v", err))
+		return
+	}
+	defer upload.Close()
+	
+	if !upload.isVideo() {
+		r.writeError(w, http.StatusUnsupportedMediaType, fmt.Sprintf("Reels require a video, got %s", upload.contentType))
+		return
+	}
+	
+	opts := &ReelOptions{
+		Description: upload.fields["description"],
+		Title:       upload.fields["title"],
+		Draft:       upload.fields["published"] == "false",
+	}
+	
+	if scheduled := upload.fields["scheduled_publish_time"]; scheduled != "" {
+		unix, err := strconv.ParseInt(scheduled, 10, 64)
+		if err != nil {
+			r.writeError(w, http.StatusBadRequest, "Invalid scheduled_publish_time parameter")
+			return
+		}
+		opts.ScheduledPublishTime = time.Unix(unix, 0)
+	}
+	
+	result, err := client.PublishReel(req.Context(), pageID, upload.file, upload.size, opts)
+	if err != nil {
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error publishing reel: %v", err))
+		return
+	}
+	
+	r.writeJSON(w, http.StatusOK, result)
+}
+	
+// publishStory handles POST /api/pages/{pageId}/stories with a photo or video
+func (r *Router) publishStory(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	pageID := vars["pageId"]
+	
+	if pageID == "" {
+		r.writeError(w, http.StatusBadRequest, "Page ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	upload, err := receiveUpload(w, req, MaxVideoUploadBytes)
+	if err != nil {
+		r.writeError(w, uploadErrorStatus(err), fmt.Sprintf("Invalid upload: %v", err))
+		return
+	}
+	defer upload.Close()
+	
+	var result *PublishResponse
+	switch {
+	case upload.isImage():
+		result, err = client.PublishPhotoStory(req.Context(), pageID, io.NewSectionReader(upload.file, 0, upload.size), &UploadOptions{Size: upload.size})
+	case upload.isVideo():
+		result, err = client.PublishVideoStory(req.Context(), pageID, upload.file, upload.size, nil)
+	default:
+		r.writeError(w, http.StatusUnsupportedMediaType, fmt.Sprintf("Stories require a photo or video, got %s", upload.contentType))
+		return
+	}
+	
+	if err != nil {
+		var mediaErr *MediaValidationError
+		if errors.As(err, &mediaErr) {
+			r.writeError(w, uploadErrorStatus(err), fmt.Sprintf("Invalid media: %v", err))
+			return
+		}
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error publishing story: %v", err))
+		return
+	}
+	
+	r.writeJSON(w, http.StatusOK, result)
+}
+	
+// getVideoStatus handles GET /api/videos/{videoId}/status
+func (r *Router) getVideoStatus(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	videoID := vars["videoId"]
+	
+	if videoID == "" {
+		r.writeError(w, http.StatusBadRequest, "Video ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	status, err := client.GetVideoStatus(req.Context(), videoID)
+	if err != nil {
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error getting video status: %v", err))
+		return
+	}
+	
+	r.writeJSON(w, http.StatusOK, status)
+}
+	
+// reconcileSubscriptions handles GET /admin/subscriptions (dry run) and
+// POST /admin/subscriptions/reconcile
+func (r *Router) reconcileSubscriptions(w http.ResponseWriter, req *http.Request) {
+	if !adminAuthorized(req, r.adminToken) {
+		r.writeError(w, http.StatusUnauthorized, "Admin token required")
+		return
+	}
+	
+	// The caller's user access token is required; the server's default
+	// token never reconciles subscriptions
+	accessToken := explicitAccessToken(req)
+	if accessToken == "" {
+		r.writeError(w, http.StatusUnauthorized, "User access token required")
+		return
+	}
+	client := r.newClient(accessToken).WithContext(req.Context())
+	
+	dryRun := req.Method == "GET" || req.URL.Query().Get("dry_run") == "true"
+	
+	results, err := client.ReconcileSubscriptions(r.subscriptionAppID, r.subscriptionFields, dryRun)
+	if err != nil {
+		if errors.Is(err, ErrAppMismatch) {
+			r.writeError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error reconciling subscriptions: %v", err))
+		return
+	}
+	
+	r.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"app_id":  r.subscriptionAppID,
+		"fields":  r.subscriptionFields,
+		"dry_run": dryRun,
+		"data":    results,
+	})
+}
+
+// healthCheck handles GET /health
+func (r *Router) healthCheck(w http.ResponseWriter, req *http.Request) {
+	version := "v23.0" // Default API version
+	if r.defaultClient != nil {
+		version = r.defaultClient.APIVersion
+	}
+	
+	r.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "ok",
+		"service": "facebook-pages-api",
+		"version": version,
+	})
+}
+
+// writeJSON writes a JSON response
+func (r *Router) writeJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// writeError writes an error response
+func (r *Router) writeError(w http.ResponseWriter, statusCode int, message string) {
+	r.writeJSON(w, statusCode, map[string]interface{}{
+		"error": message,
+		"code":  statusCode,
+	})
+}
+
+// writeQueryError writes a 400 response describing an invalid query parameter
+// or metrics rejected by the catalog
+func (r *Router) writeQueryError(w http.ResponseWriter, err error) {
+	var metricErr *MetricError
+	if errors.As(err, &metricErr) {
+		r.writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error":       metricErr.Error(),
+			"code":        http.StatusBadRequest,
+			"param":       "metric",
+			"api_version": metricErr.APIVersion,
+			"problems":    metricErr.Problems,
+		})
+		return
+	}
+	
+	var queryErr *InsightsQueryError
+	if !errors.As(err, &queryErr) {
+		r.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	
+	r.writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+		"error":  queryErr.Error(),
+		"code":   http.StatusBadRequest,
+		"param":  queryErr.Param,
+		"value":  queryErr.Value,
+		"reason": queryErr.Reason,
+	})
+}
+
+// writeInsights writes insights as JSON or streams them as CSV or NDJSON
+func (r *Router) writeInsights(w http.ResponseWriter, format string, object MetricObject, objectID string, insights *InsightsResponse) {
+	if format == "" || format == ExportJSON {
+		r.writeJSON(w, http.StatusOK, insights)
+		return
+	}
+	
+	if err := writeInsightsExport(w, format, objectID, insights); err != nil {
+		log.Printf("Error exporting %s insights for %s: %v", object, objectID, err)
+	}
+}
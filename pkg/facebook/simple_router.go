@@ -2,16 +2,31 @@ package facebook
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log"
 	"net/http"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // SimpleRouter handles HTTP routes using standard library only
 type SimpleRouter struct {
 	defaultClient *Client // Default client for backward compatibility
+	webhooks      *WebhookHandler
+	
+	messagingWindow *MessagingWindowTracker
+	
+	subscriptionAppID  string
+	subscriptionFields []string
+	adminToken         string
+	
+	insightStore *InsightStore
+	metrics      *MetricsRegistry
+	tracer       *Tracer
 }
 
 // NewSimpleRouter creates a new router without external dependencies
@@ -21,8 +36,69 @@ func NewSimpleRouter(accessToken string) *SimpleRouter {
 		defaultClient = NewClient(accessToken)
 	}
 	return &SimpleRouter{
-		defaultClient: defaultClient,
+		defaultClient:   defaultClient,
+		messagingWindow: NewMessagingWindowTracker(),
+	}
+}
+
+// MessagingWindow returns the tracker used to enforce the Messenger
+// messaging window on POST /api/pages/{pageId}/messages
+func (r *SimpleRouter) MessagingWindow() *MessagingWindowTracker {
+	return r.messagingWindow
+}
+
+// SetWebhookHandler enables the /webhooks/facebook endpoint
+func (r *SimpleRouter) SetWebhookHandler(handler *WebhookHandler) {
+	r.webhooks = handler
+}
+
+// SetDesiredSubscriptions enables the /admin/subscriptions endpoints, which
+// reconcile appID's webhook subscription on every managed page to fields.
+// Callers must send adminToken in the X-Admin-Token header and their own user
+// access token; the endpoints stay disabled without an admin token.
+func (r *SimpleRouter) SetDesiredSubscriptions(appID string, fields []string, adminToken string) {
+	r.subscriptionAppID = appID
+	r.subscriptionFields = fields
+	r.adminToken = adminToken
+}
+
+// SetMetricsRegistry enables the /metrics endpoint, which serves registry in
+// the Prometheus text format, and records request, Graph call and cache
+// metrics into it
+func (r *SimpleRouter) SetMetricsRegistry(registry *MetricsRegistry) {
+	r.metrics = registry
+	r.messagingWindow.Metrics = registry
+	if r.defaultClient != nil {
+		r.defaultClient.Metrics = registry
+	}
+}
+
+// SetTracer starts a server span for every request and a child span for
+// every Graph call made while serving it
+func (r *SimpleRouter) SetTracer(tracer *Tracer) {
+	r.tracer = tracer
+	if r.defaultClient != nil {
+		r.defaultClient.Tracer = tracer
+	}
+}
+
+// newClient creates a client for a request token that reports to the
+// router's metrics registry and tracer
+func (r *SimpleRouter) newClient(accessToken string) *Client {
+	// Keep the API version and endpoints configured on the default client
+	client := NewClient(accessToken)
+	if r.defaultClient != nil {
+		client = r.defaultClient.WithAccessToken(accessToken)
 	}
+	client.Metrics = r.metrics
+	client.Tracer = r.tracer
+	return client
+}
+
+// SetInsightStore enables the insights history endpoints, which serve points
+// collected into store
+func (r *SimpleRouter) SetInsightStore(store *InsightStore) {
+	r.insightStore = store
 }
 
 // getClientFromRequest resolves the Facebook client from request parameters or default
@@ -40,18 +116,18 @@ func (r *SimpleRouter) getClientFromRequest(req *http.Request) (*Client, error)
 	
 	// If token provided in request, create new client
 	if accessToken != "" {
-		return NewClient(accessToken), nil
+		return r.newClient(accessToken).WithContext(req.Context()), nil
 	}
 	
 	// Fall back to default client
 	if r.defaultClient != nil {
-		return r.defaultClient, nil
+		return r.defaultClient.WithContext(req.Context()), nil
 	}
 	
 	// Last resort: try environment variable
 	envToken := os.Getenv("PAGE_ACCESS_TOKEN")
 	if envToken != "" {
-		return NewClient(envToken), nil
+		return r.newClient(envToken).WithContext(req.Context()), nil
 	}
 	
 	return nil, fmt.Errorf("no access token provided - use access_token query parameter, Authorization header, or PAGE_ACCESS_TOKEN environment variable")
@@ -61,7 +137,7 @@ func (r *SimpleRouter) getClientFromRequest(req *http.Request) (*Client, error)
 func (r *SimpleRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	// Enable CORS
 	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
 	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
 	
 	if req.Method == "OPTIONS" {
@@ -71,20 +147,112 @@ func (r *SimpleRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	
 	path := req.URL.Path
 	
+	// Record request metrics and a server span by route template, set by
+	// each case below; unknown paths share one label
+	route := "unmatched"
+	if r.metrics != nil || r.tracer != nil {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		w = recorder
+		
+		var span *Span
+		req, span = startServerSpan(r.tracer, req)
+		defer func() {
+			r.metrics.observeHTTPRequest(route, req.Method, recorder.status, time.Since(start))
+			endServerSpan(span, req.Method, route, recorder.status)
+		}()
+	}
+	
 	switch {
 	case path == "/health":
+		route = "/health"
 		r.healthCheck(w, req)
+	case path == "/metrics" && r.metrics != nil:
+		route = "/metrics"
+		r.metrics.ServeHTTP(w, req)
+	case path == "/webhooks/facebook" && r.webhooks != nil:
+		route = "/webhooks/facebook"
+		r.webhooks.ServeHTTP(w, req)
+	case (path == "/admin/subscriptions" || path == "/admin/subscriptions/reconcile") && r.subscriptionAppID != "" && r.adminToken != "":
+		route = path
+		r.reconcileSubscriptions(w, req)
 	case strings.HasPrefix(path, "/api/pages/") && strings.HasSuffix(path, "/posts"):
+		route = "/api/pages/{pageId}/posts"
 		r.getPosts(w, req)
+	case strings.HasPrefix(path, "/api/pages/") && strings.HasSuffix(path, "/conversations"):
+		route = "/api/pages/{pageId}/conversations"
+		r.getConversations(w, req)
+	case strings.HasPrefix(path, "/api/pages/") && strings.HasSuffix(path, "/messages"):
+		route = "/api/pages/{pageId}/messages"
+		r.sendMessage(w, req)
+	case strings.HasPrefix(path, "/api/conversations/") && strings.HasSuffix(path, "/messages"):
+		route = "/api/conversations/{conversationId}/messages"
+		r.getConversationMessages(w, req)
+	case (strings.HasPrefix(path, "/api/pages/") || strings.HasPrefix(path, "/api/posts/")) && strings.HasSuffix(path, "/insights/history") && r.insightStore != nil:
+		route = "/api/pages/{pageId}/insights/history"
+		if strings.HasPrefix(path, "/api/posts/") {
+			route = "/api/posts/{postId}/insights/history"
+		}
+		r.getInsightsHistory(w, req)
+	case strings.HasPrefix(path, "/api/pages/") && strings.HasSuffix(path, "/insights/metrics"):
+		route = "/api/pages/{pageId}/insights/metrics"
+		r.getAvailableMetrics(w, req)
+	case strings.HasPrefix(path, "/api/pages/") && strings.HasSuffix(path, "/insights"):
+		route = "/api/pages/{pageId}/insights"
+		r.getPageInsights(w, req)
+	case strings.HasPrefix(path, "/api/posts/") && strings.HasSuffix(path, "/insights"):
+		route = "/api/posts/{postId}/insights"
+		r.getPostInsights(w, req)
+	case strings.HasPrefix(path, "/api/pages/") && strings.HasSuffix(path, "/analytics"):
+		route = "/api/pages/{pageId}/analytics"
+		r.getPageAnalytics(w, req)
+	case strings.HasPrefix(path, "/api/pages/") && strings.HasSuffix(path, "/reports/compare"):
+		route = "/api/pages/{pageId}/reports/compare"
+		r.comparePeriods(w, req)
+	case strings.HasPrefix(path, "/api/pages/") && strings.HasSuffix(path, "/photos") && req.Method == "POST":
+		route = "/api/pages/{pageId}/photos"
+		r.uploadPhoto(w, req)
+	case strings.HasPrefix(path, "/api/pages/") && strings.HasSuffix(path, "/photos"):
+		route = "/api/pages/{pageId}/photos"
+		r.getPhotos(w, req)
+	case strings.HasPrefix(path, "/api/photos/") && !strings.Contains(path[12:], "/"):
+		route = "/api/photos/{photoId}"
+		r.deletePhoto(w, req)
+	case strings.HasPrefix(path, "/api/pages/") && strings.HasSuffix(path, "/albums") && req.Method == "POST":
+		route = "/api/pages/{pageId}/albums"
+		r.createAlbum(w, req)
+	case strings.HasPrefix(path, "/api/pages/") && strings.HasSuffix(path, "/albums"):
+		route = "/api/pages/{pageId}/albums"
+		r.getAlbums(w, req)
+	case strings.HasPrefix(path, "/api/albums/") && strings.HasSuffix(path, "/photos") && req.Method == "POST":
+		route = "/api/albums/{albumId}/photos"
+		r.uploadAlbumPhoto(w, req)
+	case strings.HasPrefix(path, "/api/albums/") && strings.HasSuffix(path, "/photos"):
+		route = "/api/albums/{albumId}/photos"
+		r.getAlbumPhotos(w, req)
+	case strings.HasPrefix(path, "/api/pages/") && strings.HasSuffix(path, "/reels"):
+		route = "/api/pages/{pageId}/reels"
+		r.publishReel(w, req)
+	case strings.HasPrefix(path, "/api/pages/") && strings.HasSuffix(path, "/stories"):
+		route = "/api/pages/{pageId}/stories"
+		r.publishStory(w, req)
+	case strings.HasPrefix(path, "/api/videos/") && strings.HasSuffix(path, "/status"):
+		route = "/api/videos/{videoId}/status"
+		r.getVideoStatus(w, req)
 	case strings.HasPrefix(path, "/api/pages/") && !strings.Contains(path[11:], "/"):
+		route = "/api/pages/{pageId}"
 		r.getPage(w, req)
 	case path == "/api/pages":
+		route = "/api/pages"
 		r.getPages(w, req)
 	case strings.HasPrefix(path, "/api/posts/") && strings.HasSuffix(path, "/comments"):
+		route = "/api/posts/{postId}/comments"
 		r.getPostComments(w, req)
 	case strings.HasPrefix(path, "/api/comments/") && strings.HasSuffix(path, "/replies"):
+		route = "/api/comments/{commentId}/replies"
 		r.getCommentReplies(w, req)
 	case strings.HasPrefix(path, "/api/comments/"):
+		route = "/api/comments/{commentId}"
 		r.getComment(w, req)
 	default:
 		r.writeError(w, http.StatusNotFound, "Endpoint not found")
@@ -343,39 +511,940 @@ func (r *SimpleRouter) getCommentReplies(w http.ResponseWriter, req *http.Reques
 	r.writeJSON(w, http.StatusOK, replies)
 }
 
-// healthCheck handles GET /health
-func (r *SimpleRouter) healthCheck(w http.ResponseWriter, req *http.Request) {
+// getConversations handles GET /api/pages/{pageId}/conversations
+func (r *SimpleRouter) getConversations(w http.ResponseWriter, req *http.Request) {
 	if req.Method != "GET" {
 		r.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 	
-	version := "v23.0" // Default API version
-	if r.defaultClient != nil {
-		version = r.defaultClient.APIVersion
+	pageID := r.extractPathParam(req.URL.Path, "/api/pages/", "/conversations")
+	if pageID == "" {
+		r.writeError(w, http.StatusBadRequest, "Page ID is required")
+		return
 	}
 	
-	r.writeJSON(w, http.StatusOK, map[string]interface{}{
-		"status":  "ok",
-		"service": "facebook-pages-api",
-		"version": version,
-	})
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	// Parse limit parameter
+	limitParam := req.URL.Query().Get("limit")
+	limit := 10 // default
+	if limitParam != "" {
+		if l, err := strconv.Atoi(limitParam); err == nil && l > 0 {
+			limit = l
+		}
+	}
+	
+	// Parse fields parameter
+	fieldsParam := req.URL.Query().Get("fields")
+	var fields []string
+	if fieldsParam != "" {
+		fields = strings.Split(fieldsParam, ",")
+	}
+	
+	conversations, err := client.GetConversations(pageID, limit, fields...)
+	if err != nil {
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error getting conversations: %v", err))
+		return
+	}
+	
+	r.writeJSON(w, http.StatusOK, conversations)
 }
 
-// writeJSON writes a JSON response
-func (r *SimpleRouter) writeJSON(w http.ResponseWriter, statusCode int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
+// getConversationMessages handles GET /api/conversations/{conversationId}/messages
+func (r *SimpleRouter) getConversationMessages(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "GET" {
+		r.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
 	
-	if err := json.NewEncoder(w).Encode(data); err != nil {
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	conversationID := r.extractPathParam(req.URL.Path, "/api/conversations/", "/messages")
+	if conversationID == "" {
+		r.writeError(w, http.StatusBadRequest, "Conversation ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	// Parse limit parameter
+	limitParam := req.URL.Query().Get("limit")
+	limit := 25 // default
+	if limitParam != "" {
+		if l, err := strconv.Atoi(limitParam); err == nil && l > 0 {
+			limit = l
+		}
+	}
+	
+	// Parse fields parameter
+	fieldsParam := req.URL.Query().Get("fields")
+	var fields []string
+	if fieldsParam != "" {
+		fields = strings.Split(fieldsParam, ",")
+	}
+	
+	messages, err := client.GetConversationMessages(conversationID, limit, fields...)
+	if err != nil {
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error getting messages: %v", err))
+		return
 	}
+	
+	r.writeJSON(w, http.StatusOK, messages)
 }
 
-// writeError writes an error response
-func (r *SimpleRouter) writeError(w http.ResponseWriter, statusCode int, message string) {
-	r.writeJSON(w, statusCode, map[string]interface{}{
-		"error": message,
-		"code":  statusCode,
-	})
+// sendMessage handles POST /api/pages/{pageId}/messages
+func (r *SimpleRouter) sendMessage(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "POST" {
+		r.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	
+	pageID := r.extractPathParam(req.URL.Path, "/api/pages/", "/messages")
+	if pageID == "" {
+		r.writeError(w, http.StatusBadRequest, "Page ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	var message SendMessageRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, 1<<20)).Decode(&message); err != nil {
+		r.writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid message body: %v", err))
+		return
+	}
+	
+	if err := message.Validate(); err != nil {
+		r.writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid message: %v", err))
+		return
+	}
+	
+	result, err := client.SendMessageWithinWindow(pageID, &message, r.messagingWindow)
+	if err != nil {
+		var policyErr *MessagingPolicyError
+		if errors.As(err, &policyErr) {
+			r.writeJSON(w, http.StatusForbidden, map[string]interface{}{
+				"error":  policyErr.Error(),
+				"code":   http.StatusForbidden,
+				"policy": policyErr,
+			})
+			return
+		}
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error sending message: %v", err))
+		return
+	}
+	
+	r.writeJSON(w, http.StatusOK, result)
+}
+
+// getPageInsights handles GET /api/pages/{pageId}/insights
+func (r *SimpleRouter) getPageInsights(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "GET" {
+		r.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	
+	pageID := r.extractPathParam(req.URL.Path, "/api/pages/", "/insights")
+	if pageID == "" {
+		r.writeError(w, http.StatusBadRequest, "Page ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	query, err := ParseInsightsQuery(req.URL.Query())
+	if err != nil {
+		r.writeQueryError(w, err)
+		return
+	}
+	
+	insights, err := client.GetPageInsights(pageID, query.Metrics, query.Period, query.Since, query.Until)
+	if err != nil {
+		if errors.Is(err, ErrInvalidMetric) {
+			r.writeQueryError(w, err)
+			return
+		}
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error getting page insights: %v", err))
+		return
+	}
+	
+	r.writeInsights(w, query.Format, MetricObjectPage, pageID, insights)
+}
+
+// getInsightsHistory handles GET /api/pages/{pageId}/insights/history and
+// GET /api/posts/{postId}/insights/history
+func (r *SimpleRouter) getInsightsHistory(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "GET" {
+		r.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	
+	object, objectID := MetricObjectPage, r.extractPathParam(req.URL.Path, "/api/pages/", "/insights/history")
+	if strings.HasPrefix(req.URL.Path, "/api/posts/") {
+		object, objectID = MetricObjectPost, r.extractPathParam(req.URL.Path, "/api/posts/", "/insights/history")
+	}
+	if objectID == "" {
+		r.writeError(w, http.StatusBadRequest, "Page or post ID is required")
+		return
+	}
+	
+	query, err := ParseInsightsQuery(req.URL.Query())
+	if err != nil {
+		r.writeQueryError(w, err)
+		return
+	}
+	
+	points, err := r.insightStore.Query(query.storeQuery(objectID))
+	if err != nil {
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error reading insights history: %v", err))
+		return
+	}
+	
+	r.writeInsights(w, query.Format, object, objectID, PointsToInsights(points))
+}
+
+// getPageAnalytics handles GET /api/pages/{pageId}/analytics
+func (r *SimpleRouter) getPageAnalytics(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "GET" {
+		r.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	
+	pageID := r.extractPathParam(req.URL.Path, "/api/pages/", "/analytics")
+	if pageID == "" {
+		r.writeError(w, http.StatusBadRequest, "Page ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	opts, err := ParseAnalyticsOptions(req.URL.Query())
+	if err != nil {
+		r.writeQueryError(w, err)
+		return
+	}
+	
+	analytics, err := client.GetPageAnalytics(req.Context(), pageID, opts)
+	if err != nil {
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error getting page analytics: %v", err))
+		return
+	}
+	
+	r.writeJSON(w, http.StatusOK, analytics)
+}
+
+// comparePeriods handles GET /api/pages/{pageId}/reports/compare
+func (r *SimpleRouter) comparePeriods(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "GET" {
+		r.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	
+	pageID := r.extractPathParam(req.URL.Path, "/api/pages/", "/reports/compare")
+	if pageID == "" {
+		r.writeError(w, http.StatusBadRequest, "Page ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	opts, format, err := ParseComparisonOptions(req.URL.Query())
+	if err != nil {
+		r.writeQueryError(w, err)
+		return
+	}
+	
+	report, err := client.ComparePeriods(req.Context(), pageID, opts)
+	if err != nil {
+		if errors.Is(err, ErrInvalidMetric) {
+			r.writeQueryError(w, err)
+			return
+		}
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error comparing periods: %v", err))
+		return
+	}
+	
+	if format == ReportJSON {
+		r.writeJSON(w, http.StatusOK, report)
+		return
+	}
+	
+	w.Header().Set("Content-Type", ReportContentType(format))
+	if err := WriteComparisonReport(w, format, report); err != nil {
+		log.Printf("Error writing %s report for %s: %v", format, pageID, err)
+	}
+}
+
+// getPostInsights handles GET /api/posts/{postId}/insights
+func (r *SimpleRouter) getPostInsights(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "GET" {
+		r.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	
+	postID := r.extractPathParam(req.URL.Path, "/api/posts/", "/insights")
+	if postID == "" {
+		r.writeError(w, http.StatusBadRequest, "Post ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	query, err := ParseInsightsQuery(req.URL.Query())
+	if err != nil {
+		r.writeQueryError(w, err)
+		return
+	}
+	
+	// Post metrics are only reported over the lifetime of the post
+	if query.Period != "" && query.Period != PeriodLifetime {
+		r.writeQueryError(w, &InsightsQueryError{
+			Param:  "period",
+			Value:  query.Period,
+			Reason: "post insights only support lifetime",
+		})
+		return
+	}
+	if query.Since != nil || query.Until != nil {
+		r.writeQueryError(w, &InsightsQueryError{
+			Param:  "since",
+			Value:  req.URL.Query().Get("since"),
+			Reason: "post insights do not support a date range",
+		})
+		return
+	}
+	
+	insights, err := client.GetPostInsights(postID, query.Metrics)
+	if err != nil {
+		if errors.Is(err, ErrInvalidMetric) {
+			r.writeQueryError(w, err)
+			return
+		}
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error getting post insights: %v", err))
+		return
+	}
+	
+	r.writeInsights(w, query.Format, MetricObjectPost, postID, insights)
+}
+
+// getAvailableMetrics handles GET /api/pages/{pageId}/insights/metrics
+func (r *SimpleRouter) getAvailableMetrics(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "GET" {
+		r.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	
+	pageID := r.extractPathParam(req.URL.Path, "/api/pages/", "/insights/metrics")
+	if pageID == "" {
+		r.writeError(w, http.StatusBadRequest, "Page ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	metrics, err := client.GetAvailableMetrics(pageID)
+	if err != nil {
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error getting available metrics: %v", err))
+		return
+	}
+	
+	r.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data":        metrics,
+		"api_version": client.APIVersion,
+	})
+}
+	
+// getPhotos handles GET /api/pages/{pageId}/photos
+func (r *SimpleRouter) getPhotos(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "GET" {
+		r.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	
+	pageID := r.extractPathParam(req.URL.Path, "/api/pages/", "/photos")
+	if pageID == "" {
+		r.writeError(w, http.StatusBadRequest, "Page ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	// Parse limit parameter
+	limitParam := req.URL.Query().Get("limit")
+	limit := 25 // default
+	if limitParam != "" {
+		if l, err := strconv.Atoi(limitParam); err == nil && l > 0 {
+			limit = l
+		}
+	}
+	
+	photos, err := client.GetPhotos(pageID, limit)
+	if err != nil {
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error getting photos: %v", err))
+		return
+	}
+	
+	r.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data": photos,
+	})
+}
+
+// uploadPhoto handles POST /api/pages/{pageId}/photos with a multipart
+// file or a JSON body with a url
+func (r *SimpleRouter) uploadPhoto(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "POST" {
+		r.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	
+	pageID := r.extractPathParam(req.URL.Path, "/api/pages/", "/photos")
+	if pageID == "" {
+		r.writeError(w, http.StatusBadRequest, "Page ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	upload, err := readPhotoUpload(w, req, DefaultPhotoLimits.MaxBytes)
+	if err != nil {
+		r.writeError(w, uploadErrorStatus(err), fmt.Sprintf("Invalid upload: %v", err))
+		return
+	}
+	
+	var photo *PhotoResponse
+	if upload.URL != "" {
+		photo, err = client.UploadPhotoByURL(pageID, upload.URL, upload.Message, upload.published())
+	} else {
+		photo, err = client.UploadPhotoFromReaderContext(req.Context(), pageID, upload.file, upload.Message, upload.published(), nil)
+	}
+	
+	if err != nil {
+		var mediaErr *MediaValidationError
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &mediaErr) || errors.As(err, &maxBytesErr) {
+			r.writeError(w, uploadErrorStatus(err), fmt.Sprintf("Invalid media: %v", err))
+			return
+		}
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error uploading photo: %v", err))
+		return
+	}
+	
+	r.writeJSON(w, http.StatusCreated, photo)
+}
+
+// deletePhoto handles DELETE /api/photos/{photoId}
+func (r *SimpleRouter) deletePhoto(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "DELETE" {
+		r.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	
+	photoID := r.extractPathParam(req.URL.Path, "/api/photos/", "")
+	if photoID == "" {
+		r.writeError(w, http.StatusBadRequest, "Photo ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	if err := client.DeletePhoto(photoID); err != nil {
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error deleting photo: %v", err))
+		return
+	}
+	
+	r.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+	})
+}
+
+// getAlbums handles GET /api/pages/{pageId}/albums
+func (r *SimpleRouter) getAlbums(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "GET" {
+		r.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	
+	pageID := r.extractPathParam(req.URL.Path, "/api/pages/", "/albums")
+	if pageID == "" {
+		r.writeError(w, http.StatusBadRequest, "Page ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	// Parse limit parameter
+	limitParam := req.URL.Query().Get("limit")
+	limit := 25 // default
+	if limitParam != "" {
+		if l, err := strconv.Atoi(limitParam); err == nil && l > 0 {
+			limit = l
+		}
+	}
+	
+	// Parse fields parameter
+	fieldsParam := req.URL.Query().Get("fields")
+	var fields []string
+	if fieldsParam != "" {
+		fields = strings.Split(fieldsParam, ",")
+	}
+	
+	albums, err := client.GetAlbums(pageID, limit, req.URL.Query().Get("after"), fields...)
+	if err != nil {
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error getting albums: %v", err))
+		return
+	}
+	
+	r.writeJSON(w, http.StatusOK, albums)
+}
+
+// createAlbum handles POST /api/pages/{pageId}/albums
+func (r *SimpleRouter) createAlbum(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "POST" {
+		r.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	
+	pageID := r.extractPathParam(req.URL.Path, "/api/pages/", "/albums")
+	if pageID == "" {
+		r.writeError(w, http.StatusBadRequest, "Page ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	var albumReq struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, 1<<20)).Decode(&albumReq); err != nil {
+		r.writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid album body: %v", err))
+		return
+	}
+	
+	if albumReq.Name == "" {
+		r.writeError(w, http.StatusBadRequest, "Album name is required")
+		return
+	}
+	
+	album, err := client.CreateAlbum(pageID, albumReq.Name, albumReq.Description)
+	if err != nil {
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error creating album: %v", err))
+		return
+	}
+	
+	r.writeJSON(w, http.StatusCreated, album)
+}
+
+// getAlbumPhotos handles GET /api/albums/{albumId}/photos
+func (r *SimpleRouter) getAlbumPhotos(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "GET" {
+		r.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	
+	albumID := r.extractPathParam(req.URL.Path, "/api/albums/", "/photos")
+	if albumID == "" {
+		r.writeError(w, http.StatusBadRequest, "Album ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	// Parse limit parameter
+	limitParam := req.URL.Query().Get("limit")
+	limit := 25 // default
+	if limitParam != "" {
+		if l, err := strconv.Atoi(limitParam); err == nil && l > 0 {
+			limit = l
+		}
+	}
+	
+	// Parse fields parameter
+	fieldsParam := req.URL.Query().Get("fields")
+	var fields []string
+	if fieldsParam != "" {
+		fields = strings.Split(fieldsParam, ",")
+	}
+	
+	photos, err := client.GetAlbumPhotos(albumID, limit, req.URL.Query().Get("after"), fields...)
+	if err != nil {
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error getting album photos: %v", err))
+		return
+	}
+	
+	r.writeJSON(w, http.StatusOK, photos)
+}
+
+// uploadAlbumPhoto handles POST /api/albums/{albumId}/photos with a
+// multipart file or a JSON body with a url, like uploadPhoto
+func (r *SimpleRouter) uploadAlbumPhoto(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "POST" {
+		r.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	
+	albumID := r.extractPathParam(req.URL.Path, "/api/albums/", "/photos")
+	if albumID == "" {
+		r.writeError(w, http.StatusBadRequest, "Album ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	upload, err := readPhotoUpload(w, req, DefaultPhotoLimits.MaxBytes)
+	if err != nil {
+		r.writeError(w, uploadErrorStatus(err), fmt.Sprintf("Invalid upload: %v", err))
+		return
+	}
+	
+	var photo *PhotoResponse
+	if upload.URL != "" {
+		photo, err = client.UploadPhotoByURL(albumID, upload.URL, upload.Message, true)
+	} else {
+		photo, err = client.UploadPhotoToAlbumFromReader(req.Context(), albumID, upload.file, upload.Message, nil)
+	}
+	
+	if err != nil {
+		var mediaErr *MediaValidationError
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &mediaErr) || errors.As(err, &maxBytesErr) {
+			r.writeError(w, uploadErrorStatus(err), fmt.Sprintf("Invalid media: %v", err))
+			return
+		}
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error uploading photo to album: %v", err))
+		return
+	}
+	
+	r.writeJSON(w, http.StatusCreated, photo)
+}
+
+// publishReel handles POST /api/pages/{pageId}/reels
+func (r *SimpleRouter) publishReel(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "POST" {
+		r.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	
+	pageID := r.extractPathParam(req.URL.Path, "/api/pages/", "/reels")
+	if pageID == "" {
+		r.writeError(w, http.StatusBadRequest, "Page ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	upload, err := receiveUpload(w, req, MaxVideoUploadBytes)
+	if err != nil {
+		r.writeError(w, uploadErrorStatus(err), fmt.Sprintf("Invalid upload: %v", err))
+		return
+	}
+	defer upload.Close()
+	
+	if !upload.isVideo() {
+		r.writeError(w, http.StatusUnsupportedMediaType, fmt.Sprintf("Reels require a video, got %s", upload.contentType))
+		return
+	}
+	
+	opts := &ReelOptions{
+		Description: upload.fields["description"],
+		Title:       upload.fields["title"],
+		Draft:       upload.fields["published"] == "false",
+	}
+	
+	if scheduled := upload.fields["scheduled_publish_time"]; scheduled != "" {
+		unix, err := strconv.ParseInt(scheduled, 10, 64)
+		if err != nil {
+			r.writeError(w, http.StatusBadRequest, "Invalid scheduled_publish_time parameter")
+			return
+		}
+		opts.ScheduledPublishTime = time.Unix(unix, 0)
+	}
+	
+	result, err := client.PublishReel(req.Context(), pageID, upload.file, upload.size, opts)
+	if err != nil {
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error publishing reel: %v", err))
+		return
+	}
+	
+	r.writeJSON(w, http.StatusOK, result)
+}
+	
+// publishStory handles POST /api/pages/{pageId}/stories with a photo or video
+func (r *SimpleRouter) publishStory(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "POST" {
+		r.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	
+	pageID := r.extractPathParam(req.URL.Path, "/api/pages/", "/stories")
+	if pageID == "" {
+		r.writeError(w, http.StatusBadRequest, "Page ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	upload, err := receiveUpload(w, req, MaxVideoUploadBytes)
+	if err != nil {
+		r.writeError(w, uploadErrorStatus(err), fmt.Sprintf("Invalid upload: %v", err))
+		return
+	}
+	defer upload.Close()
+	
+	var result *PublishResponse
+	switch {
+	case upload.isImage():
+		result, err = client.PublishPhotoStory(req.Context(), pageID, io.NewSectionReader(upload.file, 0, upload.size), &UploadOptions{Size: upload.size})
+	case upload.isVideo():
+		result, err = client.PublishVideoStory(req.Context(), pageID, upload.file, upload.size, nil)
+	default:
+		r.writeError(w, http.StatusUnsupportedMediaType, fmt.Sprintf("Stories require a photo or video, got %s", upload.contentType))
+		return
+	}
+	
+	if err != nil {
+		var mediaErr *MediaValidationError
+		if errors.As(err, &mediaErr) {
+			r.writeError(w, uploadErrorStatus(err), fmt.Sprintf("Invalid media: %v", err))
+			return
+		}
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error publishing story: %v", err))
+		return
+	}
+	
+	r.writeJSON(w, http.StatusOK, result)
+}
+	
+// getVideoStatus handles GET /api/videos/{videoId}/status
+func (r *SimpleRouter) getVideoStatus(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "GET" {
+		r.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	
+	videoID := r.extractPathParam(req.URL.Path, "/api/videos/", "/status")
+	if videoID == "" {
+		r.writeError(w, http.StatusBadRequest, "Video ID is required")
+		return
+	}
+	
+	// Get client from request
+	client, err := r.getClientFromRequest(req)
+	if err != nil {
+		r.writeError(w, http.StatusUnauthorized, fmt.Sprintf("Authentication error: %v", err))
+		return
+	}
+	
+	status, err := client.GetVideoStatus(req.Context(), videoID)
+	if err != nil {
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error getting video status: %v", err))
+		return
+	}
+	
+	r.writeJSON(w, http.StatusOK, status)
+}
+	
+// reconcileSubscriptions handles GET /admin/subscriptions (dry run) and
+// POST /admin/subscriptions/reconcile
+func (r *SimpleRouter) reconcileSubscriptions(w http.ResponseWriter, req *http.Request) {
+	reconcile := req.URL.Path == "/admin/subscriptions/reconcile"
+	if (reconcile && req.Method != "POST") || (!reconcile && req.Method != "GET") {
+		r.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	
+	if !adminAuthorized(req, r.adminToken) {
+		r.writeError(w, http.StatusUnauthorized, "Admin token required")
+		return
+	}
+	
+	// The caller's user access token is required; the server's default
+	// token never reconciles subscriptions
+	accessToken := explicitAccessToken(req)
+	if accessToken == "" {
+		r.writeError(w, http.StatusUnauthorized, "User access token required")
+		return
+	}
+	client := r.newClient(accessToken).WithContext(req.Context())
+	
+	dryRun := req.Method == "GET" || req.URL.Query().Get("dry_run") == "true"
+	
+	results, err := client.ReconcileSubscriptions(r.subscriptionAppID, r.subscriptionFields, dryRun)
+	if err != nil {
+		if errors.Is(err, ErrAppMismatch) {
+			r.writeError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		r.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Error reconciling subscriptions: %v", err))
+		return
+	}
+	
+	r.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"app_id":  r.subscriptionAppID,
+		"fields":  r.subscriptionFields,
+		"dry_run": dryRun,
+		"data":    results,
+	})
+}
+
+// healthCheck handles GET /health
+func (r *SimpleRouter) healthCheck(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "GET" {
+		r.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	
+	version := "v23.0" // Default API version
+	if r.defaultClient != nil {
+		version = r.defaultClient.APIVersion
+	}
+	
+	r.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "ok",
+		"service": "facebook-pages-api",
+		"version": version,
+	})
+}
+
+// writeJSON writes a JSON response
+func (r *SimpleRouter) writeJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// writeError writes an error response
+func (r *SimpleRouter) writeError(w http.ResponseWriter, statusCode int, message string) {
+	r.writeJSON(w, statusCode, map[string]interface{}{
+		"error": message,
+		"code":  statusCode,
+	})
+}
+
+// writeQueryError writes a 400 response describing an invalid query parameter
+// or metrics rejected by the catalog
+func (r *SimpleRouter) writeQueryError(w http.ResponseWriter, err error) {
+	var metricErr *MetricError
+	if errors.As(err, &metricErr) {
+		r.writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error":       metricErr.Error(),
+			"code":        http.StatusBadRequest,
+			"param":       "metric",
+			"api_version": metricErr.APIVersion,
+			"problems":    metricErr.Problems,
+		})
+		return
+	}
+	
+	var queryErr *InsightsQueryError
+	if !errors.As(err, &queryErr) {
+		r.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	
+	r.writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+		"error":  queryErr.Error(),
+		"code":   http.StatusBadRequest,
+		"param":  queryErr.Param,
+		"value":  queryErr.Value,
+		"reason": queryErr.Reason,
+	})
+}
+
+// writeInsights writes insights as JSON or streams them as CSV or NDJSON
+func (r *SimpleRouter) writeInsights(w http.ResponseWriter, format string, object MetricObject, objectID string, insights *InsightsResponse) {
+	if format == "" || format == ExportJSON {
+		r.writeJSON(w, http.StatusOK, insights)
+		return
+	}
+	
+	if err := writeInsightsExport(w, format, objectID, insights); err != nil {
+		log.Printf("Error exporting %s insights for %s: %v", object, objectID, err)
+	}
 }
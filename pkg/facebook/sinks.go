@@ -0,0 +1,225 @@
+package facebook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// EventSignatureHeader carries the HMAC signature of outbound event callbacks
+	EventSignatureHeader = "X-Event-Signature"
+	// EventIDHeader carries the ID of the delivered event
+	EventIDHeader = "X-Event-ID"
+	// EventTypeHeader carries the type of the delivered event
+	EventTypeHeader = "X-Event-Type"
+)
+
+// HTTPSink delivers events as signed JSON POST callbacks
+type HTTPSink struct {
+	URL        string
+	Secret     string
+	HTTPClient *http.Client
+}
+
+// NewHTTPSink creates a callback sink that signs each body with secret
+func NewHTTPSink(callbackURL, secret string) *HTTPSink {
+	return &HTTPSink{
+		URL:    callbackURL,
+		Secret: secret,
+		HTTPClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Name implements Sink
+func (s *HTTPSink) Name() string {
+	return "http:" + s.URL
+}
+
+// Deliver implements Sink
+func (s *HTTPSink) Deliver(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshaling event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventIDHeader, event.ID)
+	req.Header.Set(EventTypeHeader, string(event.Type))
+	if s.Secret != "" {
+		req.Header.Set(EventSignatureHeader, SignEvent(s.Secret, time.Now(), body))
+	}
+
+	resp, err := s.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("making request: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("callback returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// SignEvent computes the X-Event-Signature header value for a callback body.
+// The format is "t=<unix seconds>,sha256=<hex hmac>", where the HMAC covers
+// "<unix seconds>.<body>" so receivers can reject replayed deliveries.
+func SignEvent(secret string, timestamp time.Time, body []byte) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	return fmt.Sprintf("t=%s,sha256=%s", ts, eventMAC(secret, ts, body))
+}
+
+// VerifyEventSignature validates an X-Event-Signature header. Signatures
+// older than tolerance are rejected; a zero tolerance disables the check.
+func VerifyEventSignature(secret, header string, body []byte, tolerance time.Duration) error {
+	var ts, signature string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			ts = value
+		case "sha256":
+			signature = value
+		}
+	}
+
+	if ts == "" || signature == "" {
+		return fmt.Errorf("missing or malformed signature")
+	}
+
+	if tolerance > 0 {
+		unix, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			return fmt.Errorf("malformed signature timestamp: %w", err)
+		}
+		if age := time.Since(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+			return fmt.Errorf("signature timestamp outside tolerance")
+		}
+	}
+
+	if !hmac.Equal([]byte(eventMAC(secret, ts, body)), []byte(signature)) {
+		return fmt.Errorf("signature mismatch")
+	}
+
+	return nil
+}
+
+func eventMAC(secret, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// NDJSONSink appends events to a newline-delimited JSON file
+type NDJSONSink struct {
+	path string
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewNDJSONSink opens (or creates) an NDJSON file for appending events
+func NewNDJSONSink(path string) (*NDJSONSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("opening NDJSON file: %w", err)
+	}
+
+	return &NDJSONSink{path: path, file: file}, nil
+}
+
+// Name implements Sink
+func (s *NDJSONSink) Name() string {
+	return "ndjson:" + s.path
+}
+
+// Deliver implements Sink
+func (s *NDJSONSink) Deliver(ctx context.Context, event Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshaling event: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("writing event: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the underlying file
+func (s *NDJSONSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file.Close()
+}
+
+// ChannelSink exposes events on a Go channel for embedding applications
+type ChannelSink struct {
+	name    string
+	events  chan Event
+	Timeout time.Duration
+}
+
+// NewChannelSink creates an in-memory sink with the given buffer size.
+// Deliveries wait up to Timeout while the buffer is full, so a slow consumer
+// shows up as retries and eventually dead-letter records rather than silent drops.
+func NewChannelSink(name string, buffer int) *ChannelSink {
+	return &ChannelSink{
+		name:    name,
+		events:  make(chan Event, buffer),
+		Timeout: 5 * time.Second,
+	}
+}
+
+// Name implements Sink
+func (s *ChannelSink) Name() string {
+	return "channel:" + s.name
+}
+
+// Events returns the channel consumers read from
+func (s *ChannelSink) Events() <-chan Event {
+	return s.events
+}
+
+// Deliver implements Sink
+func (s *ChannelSink) Deliver(ctx context.Context, event Event) error {
+	if s.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.Timeout)
+		defer cancel()
+	}
+
+	select {
+	case s.events <- event:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("channel full: %w", ctx.Err())
+	}
+}
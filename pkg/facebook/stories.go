@@ -0,0 +1,66 @@
+package facebook
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+)
+
+// StoryOptions tunes a video story upload
+type StoryOptions struct {
+	// Retry controls retries of the binary upload; defaults to
+	// DefaultChunkRetryPolicy
+	Retry *RetryPolicy
+	// Progress is called as video bytes are sent
+	Progress UploadProgressFunc
+}
+
+// PublishVideoStory initializes a video story, uploads the video and
+// publishes it
+func (c *Client) PublishVideoStory(ctx context.Context, pageID string, reader io.ReaderAt, size int64, opts *StoryOptions) (*PublishResponse, error) {
+	if opts == nil {
+		opts = &StoryOptions{}
+	}
+
+	session, err := c.startVideoPublish(ctx, pageID, "video_stories")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.uploadHostedVideo(ctx, session, reader, size, opts.Retry, opts.Progress); err != nil {
+		return nil, err
+	}
+
+	return c.finishVideoPublish(ctx, pageID, "video_stories", session, nil)
+}
+
+// PublishPhotoStory uploads a photo unpublished and publishes it as a story.
+// The photo is validated like UploadPhotoFromReaderContext.
+func (c *Client) PublishPhotoStory(ctx context.Context, pageID string, reader io.Reader, opts *UploadOptions) (*PublishResponse, error) {
+	photo, err := c.UploadPhotoFromReaderContext(ctx, pageID, reader, "", false, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("photo_id", photo.ID)
+
+	endpoint := fmt.Sprintf("%s/photo_stories", pageID)
+	resp, err := c.makeRequestContext(ctx, "POST", endpoint, params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("publishing photo story: %w", err)
+	}
+
+	var publishResp PublishResponse
+	if err := c.handleResponse(resp, &publishResp); err != nil {
+		return nil, err
+	}
+
+	if !publishResp.Success {
+		return nil, fmt.Errorf("failed to publish photo story")
+	}
+
+	publishResp.ID = photo.ID
+	return &publishResp, nil
+}
@@ -0,0 +1,291 @@
+package facebook
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// AdminTokenHeader carries the admin credential required by the
+// /admin/subscriptions endpoints
+const AdminTokenHeader = "X-Admin-Token"
+
+// ErrAppMismatch is returned when an access token belongs to a different app
+// than the one whose subscriptions are reconciled
+var ErrAppMismatch = errors.New("access token belongs to a different app")
+
+// Subscription reconcile actions
+const (
+	SubscriptionUnchanged    = "unchanged"
+	SubscriptionSubscribed   = "subscribed"
+	SubscriptionUpdated      = "updated"
+	SubscriptionUnsubscribed = "unsubscribed"
+	SubscriptionFailed       = "failed"
+)
+
+// SubscribedApp represents an app subscribed to a page's webhooks
+type SubscribedApp struct {
+	ID               string   `json:"id"`
+	Name             string   `json:"name,omitempty"`
+	Link             string   `json:"link,omitempty"`
+	Category         string   `json:"category,omitempty"`
+	SubscribedFields []string `json:"subscribed_fields,omitempty"`
+}
+
+// SubscriptionResult describes what reconciling a single page did
+type SubscriptionResult struct {
+	PageID   string   `json:"page_id"`
+	PageName string   `json:"page_name,omitempty"`
+	Action   string   `json:"action"`
+	Before   []string `json:"before"`
+	After    []string `json:"after"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// GetSubscribedApps lists the apps subscribed to a page's webhooks.
+// Requires a page access token.
+func (c *Client) GetSubscribedApps(pageID string) ([]SubscribedApp, error) {
+	endpoint := fmt.Sprintf("%s/subscribed_apps", pageID)
+	resp, err := c.makeRequest("GET", endpoint, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting subscribed apps: %w", err)
+	}
+
+	var appsResp struct {
+		Data []SubscribedApp `json:"data"`
+	}
+
+	if err := c.handleResponse(resp, &appsResp); err != nil {
+		return nil, err
+	}
+
+	return appsResp.Data, nil
+}
+
+// SubscribeApp subscribes the token's app to a page with the given fields.
+// Subscribing again replaces the previously subscribed fields. The app is
+// implied by the token, so callers acting for a specific app should check it
+// with GetTokenApp first, as ReconcileSubscriptions does.
+// Requires a page access token.
+func (c *Client) SubscribeApp(pageID string, fields []string) error {
+	if len(fields) == 0 {
+		return fmt.Errorf("at least one subscribed field is required")
+	}
+
+	params := url.Values{}
+	params.Set("subscribed_fields", strings.Join(fields, ","))
+
+	endpoint := fmt.Sprintf("%s/subscribed_apps", pageID)
+	resp, err := c.makeRequest("POST", endpoint, params, nil)
+	if err != nil {
+		return fmt.Errorf("subscribing app: %w", err)
+	}
+
+	var result struct {
+		Success bool `json:"success"`
+	}
+
+	if err := c.handleResponse(resp, &result); err != nil {
+		return err
+	}
+
+	if !result.Success {
+		return fmt.Errorf("failed to subscribe app")
+	}
+
+	return nil
+}
+
+// GetTokenApp returns the app the access token was issued for
+func (c *Client) GetTokenApp() (*SubscribedApp, error) {
+	params := url.Values{}
+	params.Set("fields", "id,name,link")
+
+	resp, err := c.makeRequest("GET", "app", params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting token app: %w", err)
+	}
+
+	var app SubscribedApp
+	if err := c.handleResponse(resp, &app); err != nil {
+		return nil, err
+	}
+
+	return &app, nil
+}
+
+// UnsubscribeApp removes the token's app subscription from a page.
+// Requires a page access token.
+func (c *Client) UnsubscribeApp(pageID string) error {
+	endpoint := fmt.Sprintf("%s/subscribed_apps", pageID)
+	resp, err := c.makeRequest("DELETE", endpoint, nil, nil)
+	if err != nil {
+		return fmt.Errorf("unsubscribing app: %w", err)
+	}
+
+	var result struct {
+		Success bool `json:"success"`
+	}
+
+	if err := c.handleResponse(resp, &result); err != nil {
+		return err
+	}
+
+	if !result.Success {
+		return fmt.Errorf("failed to unsubscribe app")
+	}
+
+	return nil
+}
+
+// ReconcileSubscriptions makes appID's subscription on every page returned
+// by GetPages match fields. An empty fields list unsubscribes the app.
+// With dryRun set, the results describe the changes without applying them.
+// Requires a user access token of appID, which is verified before any page is
+// touched; each page is updated with its own page token.
+func (c *Client) ReconcileSubscriptions(appID string, fields []string, dryRun bool) ([]SubscriptionResult, error) {
+	if appID == "" {
+		return nil, fmt.Errorf("app ID is required")
+	}
+
+	// Page tokens derived from the user token belong to the same app, so
+	// checking the user token covers every SubscribeApp call below
+	app, err := c.GetTokenApp()
+	if err != nil {
+		return nil, err
+	}
+	if app.ID != appID {
+		return nil, fmt.Errorf("%w: token app %s, configured app %s", ErrAppMismatch, app.ID, appID)
+	}
+
+	pages, err := c.GetPages()
+	if err != nil {
+		return nil, err
+	}
+
+	desired := normalizeFields(fields)
+	results := make([]SubscriptionResult, 0, len(pages))
+
+	for _, page := range pages {
+		result := SubscriptionResult{
+			PageID:   page.ID,
+			PageName: page.Name,
+			After:    desired,
+		}
+
+		if page.AccessToken == "" {
+			result.Action = SubscriptionFailed
+			result.Error = "no page access token returned for page"
+			results = append(results, result)
+			continue
+		}
+
+		pageClient := c.WithAccessToken(page.AccessToken)
+
+		apps, err := pageClient.GetSubscribedApps(page.ID)
+		if err != nil {
+			result.Action = SubscriptionFailed
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+
+		current := []string{}
+		subscribed := false
+		for _, app := range apps {
+			if app.ID == appID {
+				subscribed = true
+				current = normalizeFields(app.SubscribedFields)
+				break
+			}
+		}
+		result.Before = current
+
+		switch {
+		case len(desired) == 0 && !subscribed:
+			result.Action = SubscriptionUnchanged
+		case len(desired) == 0:
+			result.Action = SubscriptionUnsubscribed
+			if !dryRun {
+				err = pageClient.UnsubscribeApp(page.ID)
+			}
+		case subscribed && equalFields(current, desired):
+			result.Action = SubscriptionUnchanged
+		case subscribed:
+			result.Action = SubscriptionUpdated
+			if !dryRun {
+				err = pageClient.SubscribeApp(page.ID, desired)
+			}
+		default:
+			result.Action = SubscriptionSubscribed
+			if !dryRun {
+				err = pageClient.SubscribeApp(page.ID, desired)
+			}
+		}
+
+		if err != nil {
+			result.Action = SubscriptionFailed
+			result.After = current
+			result.Error = err.Error()
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// adminAuthorized reports whether the request carries the admin token
+func adminAuthorized(req *http.Request, adminToken string) bool {
+	if adminToken == "" {
+		return false
+	}
+	provided := req.Header.Get(AdminTokenHeader)
+	return subtle.ConstantTimeCompare([]byte(provided), []byte(adminToken)) == 1
+}
+
+// explicitAccessToken returns the access token passed in the access_token
+// parameter or an Authorization bearer header, without falling back to a
+// server default
+func explicitAccessToken(req *http.Request) string {
+	if token := req.URL.Query().Get("access_token"); token != "" {
+		return token
+	}
+	if header := req.Header.Get("Authorization"); strings.HasPrefix(header, "Bearer ") {
+		return strings.TrimPrefix(header, "Bearer ")
+	}
+	return ""
+}
+
+// normalizeFields trims, de-duplicates and sorts subscribed field names
+func normalizeFields(fields []string) []string {
+	seen := make(map[string]bool, len(fields))
+	normalized := make([]string, 0, len(fields))
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		if field == "" || seen[field] {
+			continue
+		}
+		seen[field] = true
+		normalized = append(normalized, field)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// equalFields compares two normalized field lists
+func equalFields(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,198 @@
+package facebook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeSubscriptions serves me/accounts, app and subscribed_apps for a set of
+// pages and records subscription changes
+type fakeSubscriptions struct {
+	appID string
+
+	mu         sync.Mutex
+	subscribed map[string][]string
+	changes    []string
+}
+
+func newFakeSubscriptions(t *testing.T) (*fakeSubscriptions, *httptest.Server) {
+	fake := &fakeSubscriptions{
+		appID: "app_1",
+		subscribed: map[string][]string{
+			"page_same":    {"mention", "feed"},
+			"page_changed": {"feed"},
+		},
+	}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return fake, server
+}
+
+func (f *fakeSubscriptions) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(req.URL.Path, "/"+DefaultAPIVersion+"/")
+	switch {
+	case path == "app":
+		fmt.Fprintf(w, `{"id":%q,"name":"Test App"}`, f.appID)
+	case path == "me/accounts":
+		fmt.Fprint(w, `{"data":[
+			{"id":"page_same","name":"Same","access_token":"t1"},
+			{"id":"page_changed","name":"Changed","access_token":"t2"},
+			{"id":"page_new","name":"New","access_token":"t3"},
+			{"id":"page_no_token","name":"No token"}]}`)
+	case strings.HasSuffix(path, "/subscribed_apps"):
+		pageID := strings.TrimSuffix(path, "/subscribed_apps")
+		switch req.Method {
+		case "GET":
+			var apps []SubscribedApp
+			if fields, ok := f.subscribed[pageID]; ok {
+				apps = append(apps, SubscribedApp{ID: "other_app"}, SubscribedApp{ID: f.appID, SubscribedFields: fields})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": apps})
+		case "POST":
+			fields := req.URL.Query().Get("subscribed_fields")
+			if fields == "" {
+				req.ParseForm()
+				fields = req.Form.Get("subscribed_fields")
+			}
+			f.subscribed[pageID] = strings.Split(fields, ",")
+			f.changes = append(f.changes, "subscribe "+pageID+" "+fields)
+			fmt.Fprint(w, `{"success":true}`)
+		case "DELETE":
+			delete(f.subscribed, pageID)
+			f.changes = append(f.changes, "unsubscribe "+pageID)
+			fmt.Fprint(w, `{"success":true}`)
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeSubscriptions) recorded() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.changes...)
+}
+
+func TestReconcileSubscriptionsDryRunAndApply(t *testing.T) {
+	fake, server := newFakeSubscriptions(t)
+	client := NewClient("user_token")
+	client.BaseURL = server.URL
+
+	fields := []string{"feed", " mention", "feed"}
+
+	results, err := client.ReconcileSubscriptions("app_1", fields, true)
+	if err != nil {
+		t.Fatalf("Expected dry run to succeed, got %v", err)
+	}
+	actions := make(map[string]string)
+	for _, result := range results {
+		actions[result.PageID] = result.Action
+	}
+	want := map[string]string{
+		"page_same":     SubscriptionUnchanged,
+		"page_changed":  SubscriptionUpdated,
+		"page_new":      SubscriptionSubscribed,
+		"page_no_token": SubscriptionFailed,
+	}
+	for pageID, action := range want {
+		if actions[pageID] != action {
+			t.Errorf("Expected %s to be %s, got %s", pageID, action, actions[pageID])
+		}
+	}
+	if changes := fake.recorded(); len(changes) != 0 {
+		t.Fatalf("Expected a dry run to change nothing, got %v", changes)
+	}
+
+	if _, err := client.ReconcileSubscriptions("app_1", fields, false); err != nil {
+		t.Fatalf("Expected reconcile to succeed, got %v", err)
+	}
+	got := strings.Join(fake.recorded(), "; ")
+	if got != "subscribe page_changed feed,mention; subscribe page_new feed,mention" {
+		t.Errorf("Unexpected subscription changes: %s", got)
+	}
+
+	// An empty field list unsubscribes the app where it is subscribed
+	if _, err := client.ReconcileSubscriptions("app_1", nil, false); err != nil {
+		t.Fatalf("Expected unsubscribe to succeed, got %v", err)
+	}
+	if changes := fake.recorded(); len(changes) != 5 || !strings.HasPrefix(changes[2], "unsubscribe ") {
+		t.Errorf("Expected three unsubscribes, got %v", changes)
+	}
+}
+
+func TestReconcileSubscriptionsRejectsOtherApp(t *testing.T) {
+	fake, server := newFakeSubscriptions(t)
+	client := NewClient("user_token")
+	client.BaseURL = server.URL
+
+	_, err := client.ReconcileSubscriptions("app_2", []string{"feed"}, false)
+	if !errors.Is(err, ErrAppMismatch) {
+		t.Fatalf("Expected ErrAppMismatch, got %v", err)
+	}
+	if changes := fake.recorded(); len(changes) != 0 {
+		t.Errorf("Expected no changes for another app's token, got %v", changes)
+	}
+}
+
+func TestSubscriptionEndpointsRequireAdminAndUserToken(t *testing.T) {
+	fake, server := newFakeSubscriptions(t)
+
+	router := NewRouter("default_token")
+	router.defaultClient.BaseURL = server.URL
+	router.SetDesiredSubscriptions("app_1", []string{"feed"}, "admin_secret")
+
+	simple := NewSimpleRouter("default_token")
+	simple.defaultClient.BaseURL = server.URL
+	simple.SetDesiredSubscriptions("app_1", []string{"feed"}, "admin_secret")
+
+	handlers := map[string]http.Handler{"Router": router.SetupRoutes(), "SimpleRouter": simple}
+	for name, handler := range handlers {
+		send := func(adminToken, userToken string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest("POST", "/admin/subscriptions/reconcile?dry_run=true", nil)
+			if adminToken != "" {
+				req.Header.Set(AdminTokenHeader, adminToken)
+			}
+			if userToken != "" {
+				req.Header.Set("Authorization", "Bearer "+userToken)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			return rec
+		}
+
+		if rec := send("", "user_token"); rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s: expected 401 without admin token, got %d", name, rec.Code)
+		}
+		if rec := send("wrong", "user_token"); rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s: expected 401 with a wrong admin token, got %d", name, rec.Code)
+		}
+		if rec := send("admin_secret", ""); rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s: expected 401 without falling back to the default token, got %d", name, rec.Code)
+		}
+
+		rec := send("admin_secret", "user_token")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d: %s", name, rec.Code, rec.Body.String())
+		}
+		var body struct {
+			DryRun bool                 `json:"dry_run"`
+			Data   []SubscriptionResult `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || !body.DryRun || len(body.Data) != 4 {
+			t.Errorf("%s: unexpected response %s", name, rec.Body.String())
+		}
+	}
+
+	if changes := fake.recorded(); len(changes) != 0 {
+		t.Errorf("Expected dry runs to change nothing, got %v", changes)
+	}
+}
@@ -0,0 +1,373 @@
+package facebook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TraceparentHeader is the W3C trace context header
+const TraceparentHeader = "traceparent"
+
+// TraceID identifies a trace
+type TraceID [16]byte
+
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// IsValid reports whether the ID is not all zeros
+func (id TraceID) IsValid() bool {
+	return id != TraceID{}
+}
+
+// SpanID identifies a span within a trace
+type SpanID [8]byte
+
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// IsValid reports whether the ID is not all zeros
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+// SpanContext is the part of a span propagated across process boundaries
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+// IsValid reports whether both IDs are set
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// Traceparent formats the span context as a W3C traceparent header value
+func (sc SpanContext) Traceparent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", sc.TraceID, sc.SpanID, flags)
+}
+
+// ParseTraceparent parses a W3C traceparent header value
+func ParseTraceparent(value string) (SpanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return SpanContext{}, false
+	}
+	// Version 00 has exactly four fields; later versions may append more
+	if parts[0] == "00" && len(parts) != 4 {
+		return SpanContext{}, false
+	}
+
+	var sc SpanContext
+	var flags [1]byte
+	if !decodeHex(sc.TraceID[:], parts[1]) || !decodeHex(sc.SpanID[:], parts[2]) || !decodeHex(flags[:], parts[3]) {
+		return SpanContext{}, false
+	}
+	if !sc.IsValid() {
+		return SpanContext{}, false
+	}
+
+	sc.Sampled = flags[0]&0x01 == 1
+	return sc, true
+}
+
+// decodeHex decodes lowercase hex of exactly len(dst) bytes
+func decodeHex(dst []byte, value string) bool {
+	if len(value) != hex.EncodedLen(len(dst)) || strings.ToLower(value) != value {
+		return false
+	}
+	_, err := hex.Decode(dst, []byte(value))
+	return err == nil
+}
+
+// SpanKind describes the relationship of a span to its remote peer
+type SpanKind string
+
+// Span kinds, as in OpenTelemetry
+const (
+	SpanKindInternal SpanKind = "internal"
+	SpanKindServer   SpanKind = "server"
+	SpanKindClient   SpanKind = "client"
+)
+
+// SpanStatus is the outcome of a span
+type SpanStatus string
+
+// Span statuses, as in OpenTelemetry
+const (
+	SpanStatusUnset SpanStatus = "unset"
+	SpanStatusOK    SpanStatus = "ok"
+	SpanStatusError SpanStatus = "error"
+)
+
+// SpanData is a finished span as handed to a SpanExporter. Attribute names
+// follow OpenTelemetry semantic conventions where one exists.
+type SpanData struct {
+	Name          string                 `json:"name"`
+	Kind          SpanKind               `json:"kind"`
+	Service       string                 `json:"service"`
+	TraceID       string                 `json:"trace_id"`
+	SpanID        string                 `json:"span_id"`
+	ParentSpanID  string                 `json:"parent_span_id,omitempty"`
+	StartTime     time.Time              `json:"start_time"`
+	EndTime       time.Time              `json:"end_time"`
+	Attributes    map[string]interface{} `json:"attributes,omitempty"`
+	Status        SpanStatus             `json:"status"`
+	StatusMessage string                 `json:"status_message,omitempty"`
+}
+
+// Duration returns how long the span lasted
+func (d *SpanData) Duration() time.Duration {
+	return d.EndTime.Sub(d.StartTime)
+}
+
+// SpanExporter receives finished spans, e.g. to forward them to an
+// OpenTelemetry collector
+type SpanExporter interface {
+	ExportSpan(span SpanData)
+}
+
+// Span is an operation being traced. All methods are safe on a nil span,
+// which is what a nil Tracer starts.
+type Span struct {
+	tracer *Tracer
+	ctx    SpanContext
+
+	mu   sync.Mutex
+	data SpanData
+	done bool
+}
+
+// SpanContext returns the propagated identity of the span
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.ctx
+}
+
+// SetAttribute records a key/value attribute on the span
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.data.Attributes == nil {
+		s.data.Attributes = make(map[string]interface{})
+	}
+	s.data.Attributes[key] = value
+}
+
+// SetStatus sets the outcome of the span
+func (s *Span) SetStatus(status SpanStatus, message string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.Status = status
+	s.data.StatusMessage = message
+}
+
+// RecordError marks the span as failed with err; nil errors are ignored
+func (s *Span) RecordError(err error) {
+	if err != nil {
+		s.SetStatus(SpanStatusError, err.Error())
+	}
+}
+
+// End finishes the span and exports it. Later calls do nothing.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.done {
+		s.mu.Unlock()
+		return
+	}
+	s.done = true
+	s.data.EndTime = time.Now()
+	data := s.data
+	s.mu.Unlock()
+
+	if s.tracer.exporter != nil && s.ctx.Sampled {
+		s.tracer.exporter.ExportSpan(data)
+	}
+}
+
+// Tracer starts spans and hands finished ones to an exporter. A nil Tracer
+// is valid and traces nothing.
+type Tracer struct {
+	service  string
+	exporter SpanExporter
+}
+
+// NewTracer creates a tracer reporting spans of service to exporter
+func NewTracer(service string, exporter SpanExporter) *Tracer {
+	return &Tracer{service: service, exporter: exporter}
+}
+
+// spanContextKey is the context key of the active span
+type spanContextKey struct{}
+
+// remoteSpanContextKey is the context key of a span context received from
+// another process
+type remoteSpanContextKey struct{}
+
+// SpanFromContext returns the active span of ctx, or nil
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanContextKey{}).(*Span)
+	return span
+}
+
+// ContextWithRemoteSpanContext makes sc the parent of the next span started
+// from ctx, e.g. after parsing an inbound traceparent header
+func ContextWithRemoteSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteSpanContextKey{}, sc)
+}
+
+// Start begins a span that is a child of the active span of ctx, or of a
+// remote span context, or the root of a new trace. The returned context
+// carries the new span.
+func (t *Tracer) Start(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	if t == nil {
+		return ctx, nil
+	}
+
+	parent := SpanFromContext(ctx).SpanContext()
+	if !parent.IsValid() {
+		parent, _ = ctx.Value(remoteSpanContextKey{}).(SpanContext)
+	}
+
+	span := &Span{tracer: t}
+	if parent.IsValid() {
+		span.ctx = SpanContext{TraceID: parent.TraceID, Sampled: parent.Sampled}
+		span.data.ParentSpanID = parent.SpanID.String()
+	} else {
+		rand.Read(span.ctx.TraceID[:])
+		span.ctx.Sampled = true
+	}
+	rand.Read(span.ctx.SpanID[:])
+
+	span.data.Name = name
+	span.data.Kind = kind
+	span.data.Service = t.service
+	span.data.TraceID = span.ctx.TraceID.String()
+	span.data.SpanID = span.ctx.SpanID.String()
+	span.data.StartTime = time.Now()
+	span.data.Status = SpanStatusUnset
+
+	return context.WithValue(ctx, spanContextKey{}, span), span
+}
+
+// InMemoryExporter keeps finished spans in memory, for tests and debugging
+type InMemoryExporter struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+// NewInMemoryExporter creates an empty exporter
+func NewInMemoryExporter() *InMemoryExporter {
+	return &InMemoryExporter{}
+}
+
+// ExportSpan records a finished span
+func (e *InMemoryExporter) ExportSpan(span SpanData) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.spans = append(e.spans, span)
+}
+
+// Spans returns the finished spans in the order they ended
+func (e *InMemoryExporter) Spans() []SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return append([]SpanData(nil), e.spans...)
+}
+
+// Reset discards the recorded spans
+func (e *InMemoryExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.spans = nil
+}
+
+// JSONExporter writes finished spans as JSON lines, e.g. for a log shipper
+type JSONExporter struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewJSONExporter creates an exporter writing to w
+func NewJSONExporter(w io.Writer) *JSONExporter {
+	return &JSONExporter{encoder: json.NewEncoder(w)}
+}
+
+// ExportSpan writes a finished span; write errors are dropped
+func (e *JSONExporter) ExportSpan(span SpanData) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.encoder.Encode(span)
+}
+
+// SetName replaces the name given when the span was started
+func (s *Span) SetName(name string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.Name = name
+}
+
+// startServerSpan starts the span of an inbound request, continuing the
+// trace of its traceparent header, and returns the request carrying it
+func startServerSpan(tracer *Tracer, req *http.Request) (*http.Request, *Span) {
+	if tracer == nil {
+		return req, nil
+	}
+
+	ctx := req.Context()
+	if sc, ok := ParseTraceparent(req.Header.Get(TraceparentHeader)); ok {
+		ctx = ContextWithRemoteSpanContext(ctx, sc)
+	}
+
+	ctx, span := tracer.Start(ctx, req.Method, SpanKindServer)
+	span.SetAttribute("http.request.method", req.Method)
+	span.SetAttribute("url.path", req.URL.Path)
+	return req.WithContext(ctx), span
+}
+
+// endServerSpan names the span of an inbound request after its route and ends it
+func endServerSpan(span *Span, method, route string, status int) {
+	span.SetName(method + " " + route)
+	span.SetAttribute("http.route", route)
+	span.SetAttribute("http.response.status_code", status)
+	if status >= 500 {
+		span.SetStatus(SpanStatusError, http.StatusText(status))
+	}
+	span.End()
+}
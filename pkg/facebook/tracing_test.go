@@ -0,0 +1,54 @@
+package facebook
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouterTracesRequestAndGraphCall(t *testing.T) {
+	graph := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("X-Fb-Trace-Id", "AbCdEf")
+		w.Write([]byte(`{"id":"12345","name":"Test Page"}`))
+	}))
+	defer graph.Close()
+
+	exporter := NewInMemoryExporter()
+	router := NewRouter("token")
+	router.defaultClient.BaseURL = graph.URL
+	router.SetTracer(NewTracer("test", exporter))
+
+	req := httptest.NewRequest("GET", "/api/pages/12345", nil)
+	req.Header.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	router.SetupRoutes().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	spans := exporter.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("Expected a Graph span and a server span, got %+v", spans)
+	}
+	graphSpan, serverSpan := spans[0], spans[1]
+
+	if serverSpan.Name != "GET /api/pages/{pageId}" || serverSpan.Kind != SpanKindServer {
+		t.Errorf("Unexpected server span %+v", serverSpan)
+	}
+	if serverSpan.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || serverSpan.ParentSpanID != "00f067aa0ba902b7" {
+		t.Errorf("Expected the inbound trace context to be continued, got %+v", serverSpan)
+	}
+
+	if graphSpan.Kind != SpanKindClient || graphSpan.ParentSpanID != serverSpan.SpanID || graphSpan.TraceID != serverSpan.TraceID {
+		t.Errorf("Expected the Graph span to be a child of the server span, got %+v", graphSpan)
+	}
+	if graphSpan.Attributes["graph.endpoint"] != "/{id}" || graphSpan.Attributes["graph.api_version"] != DefaultAPIVersion ||
+		graphSpan.Attributes["graph.fbtrace_id"] != "AbCdEf" {
+		t.Errorf("Unexpected Graph span attributes %v", graphSpan.Attributes)
+	}
+
+	if _, ok := ParseTraceparent("00-00000000000000000000000000000000-00f067aa0ba902b7-01"); ok {
+		t.Error("Expected an all-zero trace ID to be rejected")
+	}
+}
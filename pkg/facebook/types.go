@@ -145,6 +145,22 @@ type Post struct {
 	IsHidden    bool         `json:"is_hidden,omitempty"`
 	Privacy     Privacy      `json:"privacy,omitempty"`
 	Actions     []Action     `json:"actions,omitempty"`
+	Shares      *PostShares  `json:"shares,omitempty"`
+	Reactions   *EdgeSummary `json:"reactions,omitempty"`
+	Comments    *EdgeSummary `json:"comments,omitempty"`
+}
+
+// PostShares is the shares field of a post
+type PostShares struct {
+	Count int `json:"count"`
+}
+
+// EdgeSummary is an edge requested only for its summary, e.g.
+// reactions.summary(total_count).limit(0)
+type EdgeSummary struct {
+	Summary struct {
+		TotalCount int `json:"total_count"`
+	} `json:"summary"`
 }
 
 // Privacy represents post privacy settings
@@ -186,12 +202,28 @@ type Photo struct {
 	Album       Album        `json:"album,omitempty"`
 	Width       int          `json:"width,omitempty"`
 	Height      int          `json:"height,omitempty"`
+	Images      []PhotoImage `json:"images,omitempty"`
+}
+
+// PhotoImage represents one resolution of a photo
+type PhotoImage struct {
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Source string `json:"source"`
 }
 
 // Album represents a photo album
 type Album struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID          string       `json:"id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Count       int          `json:"count,omitempty"`
+	Link        string       `json:"link,omitempty"`
+	Type        string       `json:"type,omitempty"`
+	CanUpload   bool         `json:"can_upload,omitempty"`
+	CoverPhoto  *Photo       `json:"cover_photo,omitempty"`
+	CreatedTime FacebookTime `json:"created_time,omitempty"`
+	UpdatedTime FacebookTime `json:"updated_time,omitempty"`
 }
 
 // PagingData represents pagination information
@@ -0,0 +1,225 @@
+package facebook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// UploadProgressFunc reports upload progress. total is -1 when the size of
+// the upload is unknown.
+type UploadProgressFunc func(sent, total int64)
+
+// UploadOptions tunes a streaming upload
+type UploadOptions struct {
+	// Size of the media in bytes. When known, the request is sent with a
+	// Content-Length instead of chunked transfer encoding and oversized
+	// media is rejected before any bytes are sent.
+	Size int64
+	// Progress is called as media bytes are sent
+	Progress UploadProgressFunc
+	// Limits overrides DefaultPhotoLimits for photo uploads
+	Limits *PhotoLimits
+}
+
+// formField is a plain multipart form field
+type formField struct {
+	name  string
+	value string
+}
+
+// multipartFile is the file part of a streamed multipart request
+type multipartFile struct {
+	fieldName   string
+	filename    string
+	contentType string
+	reader      io.Reader
+	size        int64
+	// transfer sends the request without HTTPClient's overall timeout, see doTransfer
+	transfer bool
+}
+
+// postMultipart streams a multipart/form-data POST through an io.Pipe so the
+// file is never buffered in memory. The Content-Length is computed up front
+// when the file size is known; otherwise the body is sent chunked.
+func (c *Client) postMultipart(ctx context.Context, apiURL string, fields []formField, file *multipartFile, progress UploadProgressFunc) (*http.Response, error) {
+	boundary := multipart.NewWriter(io.Discard).Boundary()
+
+	contentLength := int64(-1)
+	if file == nil || file.size > 0 {
+		overhead, err := multipartOverhead(boundary, fields, file)
+		if err != nil {
+			return nil, err
+		}
+		contentLength = overhead
+		if file != nil {
+			contentLength += file.size
+		}
+	}
+
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	writer.SetBoundary(boundary)
+
+	errCh := make(chan error, 1)
+	go func() {
+		err := writeMultipart(ctx, writer, fields, file, progress)
+		pw.CloseWithError(err)
+		errCh <- err
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, pr)
+	if err != nil {
+		pr.Close()
+		<-errCh
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.ContentLength = contentLength
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	var resp *http.Response
+	if file != nil && file.transfer {
+		resp, err = c.doTransfer(req)
+	} else {
+		resp, err = c.do(req)
+	}
+
+	// Unblock the writer if the transport stopped reading early
+	pr.Close()
+	writeErr := <-errCh
+
+	if err != nil {
+		if writeErr != nil && !errors.Is(writeErr, io.ErrClosedPipe) {
+			return nil, writeErr
+		}
+		return nil, fmt.Errorf("making upload request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// multipartOverhead computes the size of the multipart framing around the file
+func multipartOverhead(boundary string, fields []formField, file *multipartFile) (int64, error) {
+	counter := &countingWriter{}
+	writer := multipart.NewWriter(counter)
+	writer.SetBoundary(boundary)
+
+	framing := file
+	if file != nil {
+		framing = &multipartFile{
+			fieldName:   file.fieldName,
+			filename:    file.filename,
+			contentType: file.contentType,
+			reader:      strings.NewReader(""),
+		}
+	}
+
+	if err := writeMultipart(context.Background(), writer, fields, framing, nil); err != nil {
+		return 0, err
+	}
+
+	return counter.n, nil
+}
+
+// writeMultipart writes the fields followed by the file part and closes the writer
+func writeMultipart(ctx context.Context, writer *multipart.Writer, fields []formField, file *multipartFile, progress UploadProgressFunc) error {
+	for _, field := range fields {
+		if err := writer.WriteField(field.name, field.value); err != nil {
+			return fmt.Errorf("writing %s field: %w", field.name, err)
+		}
+	}
+
+	if file != nil {
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			file.fieldName, strings.ReplaceAll(file.filename, `"`, "")))
+		contentType := file.contentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		header.Set("Content-Type", contentType)
+
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return fmt.Errorf("creating form file: %w", err)
+		}
+
+		total := file.size
+		if total <= 0 {
+			total = -1
+		}
+		reader := &progressReader{ctx: ctx, reader: file.reader, total: total, progress: progress}
+
+		written, err := io.Copy(part, reader)
+		if err != nil {
+			return fmt.Errorf("copying %s data: %w", file.fieldName, err)
+		}
+		if file.size > 0 && written != file.size {
+			return fmt.Errorf("copying %s data: expected %d bytes, read %d", file.fieldName, file.size, written)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("closing multipart writer: %w", err)
+	}
+
+	return nil
+}
+
+// countingWriter counts the bytes written to it
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
+
+// progressReader reports progress and stops reading once the context is done
+type progressReader struct {
+	ctx      context.Context
+	reader   io.Reader
+	sent     int64
+	total    int64
+	progress UploadProgressFunc
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.sent += int64(n)
+		if r.progress != nil {
+			r.progress(r.sent, r.total)
+		}
+	}
+	return n, err
+}
+
+// maxBytesReader fails with ErrMediaTooLarge once more than limit bytes are read
+type maxBytesReader struct {
+	reader io.Reader
+	limit  int64
+	read   int64
+}
+
+func (r *maxBytesReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.read += int64(n)
+	if r.read > r.limit {
+		return n, &MediaValidationError{
+			Err:    ErrMediaTooLarge,
+			Detail: fmt.Sprintf("media exceeds the %d byte limit", r.limit),
+		}
+	}
+	return n, err
+}
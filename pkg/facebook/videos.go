@@ -0,0 +1,381 @@
+package facebook
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Video processing states reported by GetVideoStatus
+const (
+	VideoStatusReady      = "ready"
+	VideoStatusProcessing = "processing"
+	VideoStatusExpired    = "expired"
+	VideoStatusError      = "error"
+)
+
+// DefaultChunkRetryPolicy retries a failed chunk transfer three times
+var DefaultChunkRetryPolicy = RetryPolicy{
+	MaxAttempts:    4,
+	InitialBackoff: time.Second,
+	MaxBackoff:     30 * time.Second,
+}
+
+// VideoUploadOptions describes the video being published
+type VideoUploadOptions struct {
+	Title       string
+	Description string
+
+	// Thumbnail is an optional custom thumbnail image
+	Thumbnail io.Reader
+
+	// Unpublished uploads the video without publishing it
+	Unpublished bool
+	// ScheduledPublishTime schedules publishing; implies Unpublished
+	ScheduledPublishTime time.Time
+
+	// Retry controls retries of failed chunk transfers; defaults to
+	// DefaultChunkRetryPolicy
+	Retry *RetryPolicy
+	// Progress is called as video bytes are sent, with offsets into the file
+	Progress UploadProgressFunc
+}
+
+// VideoUploadSession is the state of a resumable upload. It can be persisted
+// and passed to ResumeVideoUpload to continue after a failure.
+type VideoUploadSession struct {
+	PageID      string `json:"page_id"`
+	VideoID     string `json:"video_id"`
+	SessionID   string `json:"upload_session_id"`
+	FileSize    int64  `json:"file_size"`
+	StartOffset int64  `json:"start_offset"`
+	EndOffset   int64  `json:"end_offset"`
+}
+
+// Complete reports whether every chunk has been transferred
+func (s *VideoUploadSession) Complete() bool {
+	return s.StartOffset >= s.EndOffset
+}
+
+// VideoUploadError is returned when a chunk transfer fails after all retries.
+// Session holds the offsets to resume from.
+type VideoUploadError struct {
+	Session VideoUploadSession
+	Err     error
+}
+
+func (e *VideoUploadError) Error() string {
+	return fmt.Sprintf("video upload interrupted at offset %d of %d: %v", e.Session.StartOffset, e.Session.FileSize, e.Err)
+}
+
+// Unwrap returns the underlying transfer error
+func (e *VideoUploadError) Unwrap() error {
+	return e.Err
+}
+
+// VideoResponse represents the result of a finished video upload
+type VideoResponse struct {
+	ID      string `json:"id"`
+	Success bool   `json:"success"`
+}
+
+// VideoStatus represents the encoding status of a video
+type VideoStatus struct {
+	VideoStatus        string     `json:"video_status"`
+	ProcessingProgress int        `json:"processing_progress"`
+	UploadingPhase     VideoPhase `json:"uploading_phase,omitempty"`
+	ProcessingPhase    VideoPhase `json:"processing_phase,omitempty"`
+	PublishingPhase    VideoPhase `json:"publishing_phase,omitempty"`
+}
+
+// VideoPhase represents the status of a single video processing phase
+type VideoPhase struct {
+	Status           string `json:"status"`
+	BytesTransferred int64  `json:"bytes_transferred,omitempty"`
+	Errors           []struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors,omitempty"`
+}
+
+// buildVideoURL constructs the API URL on the video upload host
+func (c *Client) buildVideoURL(endpoint string) string {
+	base := c.VideoBaseURL
+	if base == "" {
+		base = c.BaseURL
+	}
+	return fmt.Sprintf("%s/%s/%s", base, c.APIVersion, endpoint)
+}
+
+// UploadVideo uploads a video file to a Facebook page using the resumable
+// chunked upload protocol
+func (c *Client) UploadVideo(pageID string, videoPath string, opts *VideoUploadOptions) (*VideoResponse, error) {
+	return c.UploadVideoContext(c.requestContext(), pageID, videoPath, opts)
+}
+
+// UploadVideoContext is UploadVideo with cancellation
+func (c *Client) UploadVideoContext(ctx context.Context, pageID string, videoPath string, opts *VideoUploadOptions) (*VideoResponse, error) {
+	file, err := os.Open(videoPath)
+	if err != nil {
+		return nil, fmt.Errorf("opening video file: %w", err)
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("reading video file info: %w", err)
+	}
+
+	return c.UploadVideoFromReaderAt(ctx, pageID, file, stat.Size(), opts)
+}
+
+// UploadVideoFromReaderAt uploads a video of the given size to a Facebook page.
+// On a failed chunk the returned error is a *VideoUploadError that carries the
+// session to pass to ResumeVideoUpload.
+func (c *Client) UploadVideoFromReaderAt(ctx context.Context, pageID string, reader io.ReaderAt, size int64, opts *VideoUploadOptions) (*VideoResponse, error) {
+	session, err := c.StartVideoUpload(ctx, pageID, size)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.ResumeVideoUpload(ctx, session, reader, opts)
+}
+
+// StartVideoUpload opens a resumable upload session (upload_phase=start)
+func (c *Client) StartVideoUpload(ctx context.Context, pageID string, size int64) (*VideoUploadSession, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("video size must be positive")
+	}
+
+	fields := []formField{
+		{name: "upload_phase", value: "start"},
+		{name: "file_size", value: strconv.FormatInt(size, 10)},
+		{name: "access_token", value: c.AccessToken},
+	}
+
+	endpoint := fmt.Sprintf("%s/videos", pageID)
+	resp, err := c.postMultipart(ctx, c.buildVideoURL(endpoint), fields, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("starting video upload: %w", err)
+	}
+
+	var startResp struct {
+		VideoID         string `json:"video_id"`
+		UploadSessionID string `json:"upload_session_id"`
+		StartOffset     int64  `json:"start_offset,string"`
+		EndOffset       int64  `json:"end_offset,string"`
+	}
+
+	if err := c.handleResponse(resp, &startResp); err != nil {
+		return nil, err
+	}
+
+	return &VideoUploadSession{
+		PageID:      pageID,
+		VideoID:     startResp.VideoID,
+		SessionID:   startResp.UploadSessionID,
+		FileSize:    size,
+		StartOffset: startResp.StartOffset,
+		EndOffset:   startResp.EndOffset,
+	}, nil
+}
+
+// ResumeVideoUpload transfers the remaining chunks of a session and finishes
+// the upload. It is used both for fresh sessions and to continue after a
+// *VideoUploadError.
+func (c *Client) ResumeVideoUpload(ctx context.Context, session *VideoUploadSession, reader io.ReaderAt, opts *VideoUploadOptions) (*VideoResponse, error) {
+	if opts == nil {
+		opts = &VideoUploadOptions{}
+	}
+
+	policy := DefaultChunkRetryPolicy
+	if opts.Retry != nil {
+		policy = *opts.Retry
+	}
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+
+	for !session.Complete() {
+		var err error
+		for attempt := 1; attempt <= policy.MaxAttempts; attempt++ {
+			if err = c.transferVideoChunk(ctx, session, reader, opts.Progress); err == nil {
+				break
+			}
+			if attempt == policy.MaxAttempts || ctx.Err() != nil {
+				break
+			}
+
+			c.recordRetry("video_chunk")
+			select {
+			case <-ctx.Done():
+			case <-time.After(policy.backoff(attempt)):
+			}
+		}
+
+		if err != nil {
+			return nil, &VideoUploadError{Session: *session, Err: err}
+		}
+	}
+
+	return c.FinishVideoUpload(ctx, session, opts)
+}
+
+// transferVideoChunk sends the chunk between the session offsets
+// (upload_phase=transfer) and advances the session
+func (c *Client) transferVideoChunk(ctx context.Context, session *VideoUploadSession, reader io.ReaderAt, progress UploadProgressFunc) error {
+	chunkSize := session.EndOffset - session.StartOffset
+
+	var chunkProgress UploadProgressFunc
+	if progress != nil {
+		offset, total := session.StartOffset, session.FileSize
+		chunkProgress = func(sent, _ int64) {
+			progress(offset+sent, total)
+		}
+	}
+
+	fields := []formField{
+		{name: "upload_phase", value: "transfer"},
+		{name: "upload_session_id", value: session.SessionID},
+		{name: "start_offset", value: strconv.FormatInt(session.StartOffset, 10)},
+		{name: "access_token", value: c.AccessToken},
+	}
+
+	file := &multipartFile{
+		fieldName:   "video_file_chunk",
+		filename:    "chunk",
+		contentType: "application/octet-stream",
+		reader:      io.NewSectionReader(reader, session.StartOffset, chunkSize),
+		size:        chunkSize,
+		transfer:    true,
+	}
+
+	endpoint := fmt.Sprintf("%s/videos", session.PageID)
+	resp, err := c.postMultipart(ctx, c.buildVideoURL(endpoint), fields, file, chunkProgress)
+	if err != nil {
+		return fmt.Errorf("transferring video chunk: %w", err)
+	}
+
+	var transferResp struct {
+		StartOffset int64 `json:"start_offset,string"`
+		EndOffset   int64 `json:"end_offset,string"`
+	}
+
+	if err := c.handleResponse(resp, &transferResp); err != nil {
+		return err
+	}
+
+	session.StartOffset = transferResp.StartOffset
+	session.EndOffset = transferResp.EndOffset
+
+	return nil
+}
+
+// FinishVideoUpload closes the session (upload_phase=finish) and publishes,
+// schedules or saves the video according to opts
+func (c *Client) FinishVideoUpload(ctx context.Context, session *VideoUploadSession, opts *VideoUploadOptions) (*VideoResponse, error) {
+	if opts == nil {
+		opts = &VideoUploadOptions{}
+	}
+
+	fields := []formField{
+		{name: "upload_phase", value: "finish"},
+		{name: "upload_session_id", value: session.SessionID},
+		{name: "access_token", value: c.AccessToken},
+	}
+
+	if opts.Title != "" {
+		fields = append(fields, formField{name: "title", value: opts.Title})
+	}
+	if opts.Description != "" {
+		fields = append(fields, formField{name: "description", value: opts.Description})
+	}
+
+	if !opts.ScheduledPublishTime.IsZero() {
+		fields = append(fields,
+			formField{name: "published", value: "false"},
+			formField{name: "scheduled_publish_time", value: strconv.FormatInt(opts.ScheduledPublishTime.Unix(), 10)},
+		)
+	} else if opts.Unpublished {
+		fields = append(fields, formField{name: "published", value: "false"})
+	}
+
+	var thumb *multipartFile
+	if opts.Thumbnail != nil {
+		thumb = &multipartFile{
+			fieldName: "thumb",
+			filename:  "thumbnail.jpg",
+			reader:    opts.Thumbnail,
+		}
+	}
+
+	endpoint := fmt.Sprintf("%s/videos", session.PageID)
+	resp, err := c.postMultipart(ctx, c.buildVideoURL(endpoint), fields, thumb, nil)
+	if err != nil {
+		return nil, fmt.Errorf("finishing video upload: %w", err)
+	}
+
+	var finishResp VideoResponse
+	if err := c.handleResponse(resp, &finishResp); err != nil {
+		return nil, err
+	}
+
+	if !finishResp.Success {
+		return nil, fmt.Errorf("failed to finish video upload")
+	}
+
+	finishResp.ID = session.VideoID
+	return &finishResp, nil
+}
+
+// GetVideoStatus retrieves the upload and encoding status of a video
+func (c *Client) GetVideoStatus(ctx context.Context, videoID string) (*VideoStatus, error) {
+	params := url.Values{}
+	params.Set("fields", "status")
+
+	resp, err := c.makeRequestContext(ctx, "GET", videoID, params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting video status: %w", err)
+	}
+
+	var statusResp struct {
+		Status VideoStatus `json:"status"`
+	}
+
+	if err := c.handleResponse(resp, &statusResp); err != nil {
+		return nil, err
+	}
+
+	return &statusResp.Status, nil
+}
+
+// WaitForVideoReady polls GetVideoStatus every interval until the video is
+// ready, fails encoding, or the context is cancelled
+func (c *Client) WaitForVideoReady(ctx context.Context, videoID string, interval time.Duration) (*VideoStatus, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		status, err := c.GetVideoStatus(ctx, videoID)
+		if err != nil {
+			return nil, err
+		}
+
+		switch status.VideoStatus {
+		case VideoStatusReady:
+			return status, nil
+		case VideoStatusError, VideoStatusExpired:
+			return status, fmt.Errorf("video %s encoding failed with status %s", videoID, status.VideoStatus)
+		}
+
+		select {
+		case <-ctx.Done():
+			return status, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package facebook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestVideoUploadResumesAfterFailedChunk(t *testing.T) {
+	video := bytes.Repeat([]byte("0123456789"), 3)
+	const chunkSize = 10
+
+	var (
+		mu       sync.Mutex
+		received []byte
+		failNext bool
+		title    string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if err := req.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		switch req.FormValue("upload_phase") {
+		case "start":
+			fmt.Fprintf(w, `{"video_id":"video_1","upload_session_id":"session_1","start_offset":"0","end_offset":"%d"}`, chunkSize)
+		case "transfer":
+			if failNext {
+				failNext = false
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"error":{"message":"transient","code":1363030}}`))
+				return
+			}
+			file, _, err := req.FormFile("video_file_chunk")
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			chunk, _ := io.ReadAll(file)
+			offset, _ := strconv.Atoi(req.FormValue("start_offset"))
+			if offset != len(received) {
+				t.Errorf("Expected chunk at offset %d, got %d", len(received), offset)
+			}
+			received = append(received, chunk...)
+			failNext = len(received) == chunkSize
+
+			end := len(received) + chunkSize
+			if end > len(video) {
+				end = len(video)
+			}
+			fmt.Fprintf(w, `{"start_offset":"%d","end_offset":"%d"}`, len(received), end)
+		case "finish":
+			title = req.FormValue("title")
+			w.Write([]byte(`{"success":true}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test_token")
+	client.VideoBaseURL = server.URL
+
+	var lastSent int64
+	opts := &VideoUploadOptions{
+		Title:    "Product demo",
+		Retry:    &RetryPolicy{MaxAttempts: 1},
+		Progress: func(sent, total int64) { lastSent = sent },
+	}
+
+	_, err := client.UploadVideoFromReaderAt(context.Background(), "page", bytes.NewReader(video), int64(len(video)), opts)
+	var uploadErr *VideoUploadError
+	if !errors.As(err, &uploadErr) {
+		t.Fatalf("Expected VideoUploadError, got %v", err)
+	}
+	if uploadErr.Session.StartOffset != chunkSize {
+		t.Fatalf("Expected session to resume at %d, got %d", chunkSize, uploadErr.Session.StartOffset)
+	}
+
+	session := uploadErr.Session
+	resp, err := client.ResumeVideoUpload(context.Background(), &session, bytes.NewReader(video), opts)
+	if err != nil {
+		t.Fatalf("Expected resumed upload to succeed, got %v", err)
+	}
+	if resp.ID != "video_1" {
+		t.Errorf("Expected video ID video_1, got %s", resp.ID)
+	}
+	if !bytes.Equal(received, video) {
+		t.Errorf("Expected server to receive %q, got %q", video, received)
+	}
+	if title != "Product demo" {
+		t.Errorf("Expected title to be sent on finish, got %q", title)
+	}
+	if lastSent != int64(len(video)) {
+		t.Errorf("Expected final progress %d, got %d", len(video), lastSent)
+	}
+}
+
+func TestSimpleRouterPublishesReel(t *testing.T) {
+	video := append([]byte("\x00\x00\x00\x18ftypmp42"), bytes.Repeat([]byte{0}, 64)...)
+
+	var uploaded []byte
+	var videoState, description string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.URL.Path == "/upload/reel_1":
+			if req.Header.Get("Authorization") != "OAuth test_token" || req.Header.Get("file_size") != strconv.Itoa(len(video)) {
+				t.Errorf("Unexpected upload headers: %v", req.Header)
+			}
+			uploaded, _ = io.ReadAll(req.Body)
+			w.Write([]byte(`{"success":true}`))
+		case req.URL.Query().Get("upload_phase") == "start":
+			fmt.Fprintf(w, `{"video_id":"reel_1","upload_url":"%s/upload/reel_1"}`, server.URL)
+		case req.URL.Query().Get("upload_phase") == "finish":
+			videoState = req.URL.Query().Get("video_state")
+			description = req.URL.Query().Get("description")
+			w.Write([]byte(`{"success":true,"post_id":"page_post_1"}`))
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+	defer server.Close()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("description", "New drop")
+	part, _ := writer.CreateFormFile("file", "reel.mp4")
+	part.Write(video)
+	writer.Close()
+
+	router := NewSimpleRouter("test_token")
+	router.defaultClient.BaseURL = server.URL
+
+	req := httptest.NewRequest("POST", "/api/pages/page_1/reels", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !bytes.Equal(uploaded, video) {
+		t.Errorf("Expected the video to be uploaded unchanged")
+	}
+	if videoState != VideoStatePublished || description != "New drop" {
+		t.Errorf("Expected published reel with description, got state %q and description %q", videoState, description)
+	}
+
+	var result PublishResponse
+	json.NewDecoder(rec.Body).Decode(&result)
+	if result.ID != "reel_1" || result.PostID != "page_post_1" {
+		t.Errorf("Expected reel_1/page_post_1, got %+v", result)
+	}
+}
+
+func TestVideoTransfersIgnoreClientTimeout(t *testing.T) {
+	video := bytes.Repeat([]byte("0123456789"), 3)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		io.Copy(io.Discard, req.Body)
+		time.Sleep(100 * time.Millisecond)
+		if req.URL.Path == "/upload/reel_1" {
+			w.Write([]byte(`{"success":true}`))
+			return
+		}
+		fmt.Fprintf(w, `{"start_offset":"%d","end_offset":"%d"}`, len(video), len(video))
+	}))
+	defer server.Close()
+
+	// Slower than the client timeout, yet bounded only by the context
+	client := NewClient("test_token")
+	client.BaseURL = server.URL
+	client.VideoBaseURL = server.URL
+	client.HTTPClient.Timeout = 20 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.uploadHostedVideoFrom(ctx, server.URL+"/upload/reel_1", bytes.NewReader(video), 0, int64(len(video)), nil); err != nil {
+		t.Errorf("Expected hosted upload to outlast the client timeout, got %v", err)
+	}
+
+	session := &VideoUploadSession{PageID: "page", SessionID: "session_1", EndOffset: int64(len(video)), FileSize: int64(len(video))}
+	if err := client.transferVideoChunk(ctx, session, bytes.NewReader(video), nil); err != nil {
+		t.Errorf("Expected chunk transfer to outlast the client timeout, got %v", err)
+	}
+
+	// Regular Graph calls keep the client timeout
+	if _, err := client.GetVideoStatus(ctx, "video_1"); err == nil {
+		t.Error("Expected a regular request to hit the client timeout")
+	}
+	if client.HTTPClient.Timeout != 20*time.Millisecond {
+		t.Errorf("Expected the client timeout to be left unchanged, got %v", client.HTTPClient.Timeout)
+	}
+}
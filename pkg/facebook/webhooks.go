@@ -0,0 +1,352 @@
+package facebook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// SignatureHeader is the header Facebook uses to sign webhook payloads
+	SignatureHeader = "X-Hub-Signature-256"
+
+	// maxWebhookBodySize bounds the size of a webhook delivery we are willing to read
+	maxWebhookBodySize = 1 << 20
+)
+
+// WebhookPayload represents a webhook delivery from Facebook
+type WebhookPayload struct {
+	Object string         `json:"object"`
+	Entry  []WebhookEntry `json:"entry"`
+}
+
+// WebhookEntry represents a single page entry in a webhook delivery
+type WebhookEntry struct {
+	ID        string           `json:"id"`
+	Time      int64            `json:"time"`
+	Changes   []WebhookChange  `json:"changes,omitempty"`
+	Messaging []MessagingEvent `json:"messaging,omitempty"`
+}
+
+// WebhookChange represents a changed field in a webhook entry
+type WebhookChange struct {
+	Field string          `json:"field"`
+	Value json.RawMessage `json:"value"`
+}
+
+// WebhookSender identifies who triggered a webhook change
+type WebhookSender struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// FeedChange represents a change on the "feed" field (posts, comments, reactions)
+type FeedChange struct {
+	Item         string        `json:"item"`
+	Verb         string        `json:"verb"`
+	PostID       string        `json:"post_id,omitempty"`
+	CommentID    string        `json:"comment_id,omitempty"`
+	ParentID     string        `json:"parent_id,omitempty"`
+	Message      string        `json:"message,omitempty"`
+	From         WebhookSender `json:"from,omitempty"`
+	ReactionType string        `json:"reaction_type,omitempty"`
+	Link         string        `json:"link,omitempty"`
+	PhotoID      string        `json:"photo_id,omitempty"`
+	VideoID      string        `json:"video_id,omitempty"`
+	Published    int           `json:"published,omitempty"`
+	IsHidden     bool          `json:"is_hidden,omitempty"`
+	CreatedTime  int64         `json:"created_time,omitempty"`
+}
+
+// MentionChange represents a change on the "mention" field
+type MentionChange struct {
+	Item       string `json:"item"`
+	Verb       string `json:"verb"`
+	PostID     string `json:"post_id,omitempty"`
+	CommentID  string `json:"comment_id,omitempty"`
+	SenderID   string `json:"sender_id,omitempty"`
+	SenderName string `json:"sender_name,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
+
+// RatingChange represents a change on the "ratings" field (reviews and recommendations)
+type RatingChange struct {
+	Item               string `json:"item"`
+	Verb               string `json:"verb"`
+	ReviewerID         string `json:"reviewer_id,omitempty"`
+	ReviewerName       string `json:"reviewer_name,omitempty"`
+	ReviewText         string `json:"review_text,omitempty"`
+	Rating             int    `json:"rating,omitempty"`
+	RecommendationType string `json:"recommendation_type,omitempty"`
+	OpenGraphStoryID   string `json:"open_graph_story_id,omitempty"`
+	CommentID          string `json:"comment_id,omitempty"`
+	Message            string `json:"message,omitempty"`
+	CreatedTime        int64  `json:"created_time,omitempty"`
+}
+
+// MessagingEvent represents a Messenger event delivered to a page
+type MessagingEvent struct {
+	Sender    WebhookSender    `json:"sender"`
+	Recipient WebhookSender    `json:"recipient"`
+	Timestamp int64            `json:"timestamp"`
+	Message   *WebhookMessage  `json:"message,omitempty"`
+	Postback  *WebhookPostback `json:"postback,omitempty"`
+	Delivery  *WebhookDelivery `json:"delivery,omitempty"`
+	Read      *WebhookRead     `json:"read,omitempty"`
+}
+
+// Time returns the event timestamp as a time.Time
+func (e MessagingEvent) Time() time.Time {
+	return time.UnixMilli(e.Timestamp)
+}
+
+// WebhookMessage represents an incoming Messenger message
+type WebhookMessage struct {
+	MID         string                     `json:"mid"`
+	Text        string                     `json:"text,omitempty"`
+	IsEcho      bool                       `json:"is_echo,omitempty"`
+	QuickReply  *WebhookQuickReply         `json:"quick_reply,omitempty"`
+	Attachments []WebhookMessageAttachment `json:"attachments,omitempty"`
+}
+
+// WebhookQuickReply carries the payload of a tapped quick reply
+type WebhookQuickReply struct {
+	Payload string `json:"payload"`
+}
+
+// WebhookMessageAttachment represents an attachment on an incoming message
+type WebhookMessageAttachment struct {
+	Type    string `json:"type"`
+	Payload struct {
+		URL string `json:"url,omitempty"`
+	} `json:"payload"`
+}
+
+// WebhookPostback represents a postback button tap
+type WebhookPostback struct {
+	MID     string `json:"mid,omitempty"`
+	Title   string `json:"title"`
+	Payload string `json:"payload"`
+}
+
+// WebhookDelivery represents a message delivery receipt
+type WebhookDelivery struct {
+	MIDs      []string `json:"mids,omitempty"`
+	Watermark int64    `json:"watermark"`
+}
+
+// WebhookRead represents a message read receipt
+type WebhookRead struct {
+	Watermark int64 `json:"watermark"`
+}
+
+// Webhook handler function types. Returning an error makes the receiver
+// respond with a non-2xx status so Facebook redelivers the batch.
+type (
+	FeedHandler    func(pageID string, change FeedChange) error
+	MentionHandler func(pageID string, change MentionChange) error
+	RatingHandler  func(pageID string, change RatingChange) error
+	MessageHandler func(pageID string, event MessagingEvent) error
+)
+
+// WebhookHandler receives and dispatches Facebook page webhooks
+type WebhookHandler struct {
+	VerifyToken string
+	AppSecret   string
+
+	feedHandlers    []FeedHandler
+	mentionHandlers []MentionHandler
+	ratingHandlers  []RatingHandler
+	messageHandlers []MessageHandler
+}
+
+// NewWebhookHandler creates a webhook receiver.
+// verifyToken is the token configured in the app dashboard for the
+// hub.challenge handshake, appSecret is used to validate payload signatures.
+func NewWebhookHandler(verifyToken, appSecret string) *WebhookHandler {
+	return &WebhookHandler{
+		VerifyToken: verifyToken,
+		AppSecret:   appSecret,
+	}
+}
+
+// OnFeed registers a handler for "feed" changes
+func (h *WebhookHandler) OnFeed(handler FeedHandler) {
+	h.feedHandlers = append(h.feedHandlers, handler)
+}
+
+// OnMention registers a handler for "mention" changes
+func (h *WebhookHandler) OnMention(handler MentionHandler) {
+	h.mentionHandlers = append(h.mentionHandlers, handler)
+}
+
+// OnRating registers a handler for "ratings" changes
+func (h *WebhookHandler) OnRating(handler RatingHandler) {
+	h.ratingHandlers = append(h.ratingHandlers, handler)
+}
+
+// OnMessage registers a handler for Messenger events
+func (h *WebhookHandler) OnMessage(handler MessageHandler) {
+	h.messageHandlers = append(h.messageHandlers, handler)
+}
+
+// ServeHTTP implements http.Handler interface
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case "GET":
+		h.verify(w, req)
+	case "POST":
+		h.receive(w, req)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// verify handles the hub.challenge subscription handshake
+func (h *WebhookHandler) verify(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+
+	if query.Get("hub.mode") != "subscribe" || h.VerifyToken == "" ||
+		!hmac.Equal([]byte(query.Get("hub.verify_token")), []byte(h.VerifyToken)) {
+		http.Error(w, "Verification failed", http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, query.Get("hub.challenge"))
+}
+
+// receive handles an event delivery
+func (h *WebhookHandler) receive(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, maxWebhookBodySize))
+	if err != nil {
+		http.Error(w, "Error reading body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.VerifySignature(body, req.Header.Get(SignatureHeader)); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var payload WebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Dispatch(&payload); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// VerifySignature checks an X-Hub-Signature-256 header against the payload
+func (h *WebhookHandler) VerifySignature(body []byte, signature string) error {
+	if h.AppSecret == "" {
+		return fmt.Errorf("app secret not configured")
+	}
+
+	if !strings.HasPrefix(signature, "sha256=") {
+		return fmt.Errorf("missing or malformed signature")
+	}
+
+	expected, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return fmt.Errorf("malformed signature: %w", err)
+	}
+
+	mac := hmac.New(sha256.New, []byte(h.AppSecret))
+	mac.Write(body)
+	if !hmac.Equal(mac.Sum(nil), expected) {
+		return fmt.Errorf("signature mismatch")
+	}
+
+	return nil
+}
+
+// Dispatch decodes each change in the payload and calls every registered
+// handler. A failing handler does not stop the others; their errors are
+// joined. Delivery is at-least-once: when Dispatch fails the webhook answers
+// non-2xx and Facebook redelivers the whole batch, so handlers must tolerate
+// seeing a change again.
+func (h *WebhookHandler) Dispatch(payload *WebhookPayload) error {
+	if payload.Object != "page" {
+		return nil
+	}
+
+	var errs []error
+	for _, entry := range payload.Entry {
+		for _, change := range entry.Changes {
+			if err := h.dispatchChange(entry.ID, change); err != nil {
+				errs = append(errs, fmt.Errorf("handling %s change for page %s: %w", change.Field, entry.ID, err))
+			}
+		}
+
+		for _, event := range entry.Messaging {
+			if err := h.dispatchMessage(entry.ID, event); err != nil {
+				errs = append(errs, fmt.Errorf("handling message for page %s: %w", entry.ID, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// dispatchChange decodes a single change into its typed struct
+func (h *WebhookHandler) dispatchChange(pageID string, change WebhookChange) error {
+	var errs []error
+	switch change.Field {
+	case "feed":
+		var value FeedChange
+		if err := json.Unmarshal(change.Value, &value); err != nil {
+			return fmt.Errorf("decoding feed change: %w", err)
+		}
+		for _, handler := range h.feedHandlers {
+			errs = append(errs, handler(pageID, value))
+		}
+	case "mention":
+		var value MentionChange
+		if err := json.Unmarshal(change.Value, &value); err != nil {
+			return fmt.Errorf("decoding mention change: %w", err)
+		}
+		for _, handler := range h.mentionHandlers {
+			errs = append(errs, handler(pageID, value))
+		}
+	case "ratings":
+		var value RatingChange
+		if err := json.Unmarshal(change.Value, &value); err != nil {
+			return fmt.Errorf("decoding ratings change: %w", err)
+		}
+		for _, handler := range h.ratingHandlers {
+			errs = append(errs, handler(pageID, value))
+		}
+	case "messages":
+		var value MessagingEvent
+		if err := json.Unmarshal(change.Value, &value); err != nil {
+			return fmt.Errorf("decoding messages change: %w", err)
+		}
+		return h.dispatchMessage(pageID, value)
+	}
+
+	return errors.Join(errs...)
+}
+
+// dispatchMessage calls the registered Messenger handlers
+func (h *WebhookHandler) dispatchMessage(pageID string, event MessagingEvent) error {
+	var errs []error
+	for _, handler := range h.messageHandlers {
+		errs = append(errs, handler(pageID, event))
+	}
+	return errors.Join(errs...)
+}
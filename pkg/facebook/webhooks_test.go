@@ -0,0 +1,139 @@
+package facebook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func signPayload(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestWebhookVerificationHandshake(t *testing.T) {
+	handler := NewWebhookHandler("verify_me", "secret")
+
+	req := httptest.NewRequest("GET", "/webhooks/facebook?hub.mode=subscribe&hub.verify_token=verify_me&hub.challenge=12345", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+	if rec.Body.String() != "12345" {
+		t.Errorf("Expected challenge to be echoed, got %q", rec.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/webhooks/facebook?hub.mode=subscribe&hub.verify_token=wrong&hub.challenge=12345", nil)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for wrong verify token, got %d", rec.Code)
+	}
+}
+
+func TestWebhookRejectsBadSignature(t *testing.T) {
+	handler := NewWebhookHandler("verify_me", "secret")
+	body := `{"object":"page","entry":[]}`
+
+	req := httptest.NewRequest("POST", "/webhooks/facebook", strings.NewReader(body))
+	req.Header.Set(SignatureHeader, signPayload("other_secret", body))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", rec.Code)
+	}
+}
+
+func TestWebhookDispatch(t *testing.T) {
+	handler := NewWebhookHandler("verify_me", "secret")
+
+	var feed []FeedChange
+	var mentions []MentionChange
+	var messages []MessagingEvent
+	handler.OnFeed(func(pageID string, change FeedChange) error {
+		if pageID != "123" {
+			t.Errorf("Expected page ID 123, got %s", pageID)
+		}
+		feed = append(feed, change)
+		return nil
+	})
+	handler.OnMention(func(pageID string, change MentionChange) error {
+		mentions = append(mentions, change)
+		return nil
+	})
+	handler.OnMessage(func(pageID string, event MessagingEvent) error {
+		messages = append(messages, event)
+		return nil
+	})
+
+	body := `{"object":"page","entry":[{"id":"123","time":1700000000,
+		"changes":[
+			{"field":"feed","value":{"item":"comment","verb":"add","post_id":"123_1","comment_id":"1_2","message":"hi","from":{"id":"9","name":"Jane"}}},
+			{"field":"mention","value":{"item":"post","verb":"add","post_id":"456_7"}}
+		],
+		"messaging":[{"sender":{"id":"psid"},"recipient":{"id":"123"},"timestamp":1700000000000,"message":{"mid":"m1","text":"hello"}}]
+	}]}`
+
+	req := httptest.NewRequest("POST", "/webhooks/facebook", strings.NewReader(body))
+	req.Header.Set(SignatureHeader, signPayload("secret", body))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if len(feed) != 1 || feed[0].CommentID != "1_2" || feed[0].From.Name != "Jane" {
+		t.Errorf("Unexpected feed changes: %+v", feed)
+	}
+	if len(mentions) != 1 || mentions[0].PostID != "456_7" {
+		t.Errorf("Unexpected mention changes: %+v", mentions)
+	}
+	if len(messages) != 1 || messages[0].Message == nil || messages[0].Message.Text != "hello" {
+		t.Errorf("Unexpected messaging events: %+v", messages)
+	}
+}
+
+func TestWebhookDispatchRunsEveryHandler(t *testing.T) {
+	handler := NewWebhookHandler("verify_me", "secret")
+
+	var calls []string
+	handler.OnFeed(func(pageID string, change FeedChange) error {
+		calls = append(calls, "feed-1")
+		return errors.New("downstream unavailable")
+	})
+	handler.OnFeed(func(pageID string, change FeedChange) error {
+		calls = append(calls, "feed-2")
+		return nil
+	})
+	handler.OnMention(func(pageID string, change MentionChange) error {
+		calls = append(calls, "mention")
+		return nil
+	})
+
+	payload := &WebhookPayload{Object: "page", Entry: []WebhookEntry{{
+		ID: "123",
+		Changes: []WebhookChange{
+			{Field: "feed", Value: []byte(`{"item":"post","verb":"add","post_id":"123_1"}`)},
+			{Field: "mention", Value: []byte(`{"item":"post","verb":"add","post_id":"456_7"}`)},
+		},
+	}}}
+
+	err := handler.Dispatch(payload)
+	if err == nil || !strings.Contains(err.Error(), "downstream unavailable") {
+		t.Errorf("Expected the handler error to be reported, got %v", err)
+	}
+	if strings.Join(calls, ",") != "feed-1,feed-2,mention" {
+		t.Errorf("Expected every handler to run, got %v", calls)
+	}
+}